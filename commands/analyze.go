@@ -0,0 +1,88 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/golangchallenge/gc6/mazelib"
+	"github.com/golangchallenge/gc6/mazelib/analysis"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Defining the analyze command.
+// This will be called as 'laybrinth analyze'
+var analyzeCmd = &cobra.Command{
+	Use:   "analyze",
+	Short: "Print difficulty metrics for a laybrinth",
+	Long: `Analyze the maze given with --maze, or a freshly generated one,
+  and print metrics such as the shortest path to the treasure, dead ends,
+  junctions, loops and the expected steps for a depth first solver.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		asJSON, _ := cmd.Flags().GetBool("json")
+		if err := runAnalyze(asJSON); err != nil {
+			fmt.Println(err)
+			os.Exit(-1)
+		}
+	},
+}
+
+func init() {
+	analyzeCmd.Flags().Bool("json", false, "print the report as JSON")
+	RootCmd.AddCommand(analyzeCmd)
+}
+
+func runAnalyze(asJSON bool) error {
+	seed := viper.GetInt64("seed")
+	m, err := createMaze(seed)
+	if err != nil {
+		return err
+	}
+	r := analysis.Analyze(m, solverRand(seed))
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	}
+
+	mazelib.PrintMaze(m)
+	fmt.Printf("Maze %dx%d", r.Width, r.Height)
+	if m.Generator != "" {
+		fmt.Printf(" (generator %s, seed %d)", m.Generator, m.Seed)
+	}
+	fmt.Println()
+	fmt.Printf("Shortest path:        %d\n", r.ShortestPath)
+	fmt.Printf("Dead ends:            %d\n", r.DeadEnds)
+	fmt.Printf("Junctions:            %d\n", r.Junctions)
+	fmt.Printf("Degree distribution:  ")
+	for d, n := range r.Degrees {
+		fmt.Printf("%d:%d ", d, n)
+	}
+	fmt.Println()
+	fmt.Printf("Longest corridor:     %d\n", r.LongestCorridor)
+	fmt.Printf("Loops:                %d\n", r.Cyclomatic)
+	fmt.Printf("Components:           %d\n", r.Components)
+	how := "estimated"
+	if r.ExpectedDFSExact {
+		how = "exact"
+	}
+	fmt.Printf("Expected DFS steps:   %.1f (%s)\n", r.ExpectedDFSSteps, how)
+	return nil
+}
@@ -0,0 +1,217 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/golangchallenge/gc6/generators"
+	"github.com/golangchallenge/gc6/mazelib"
+	"github.com/golangchallenge/gc6/solvers"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Defining the arena command.
+// This will be called as 'laybrinth arena'
+var arenaCmd = &cobra.Command{
+	Use:   "arena",
+	Short: "Pit every maze generator against every solver",
+	Long: `The arena runs every registered generator against every registered solver,
+  for each requested maze size, solving each pairing many times in-process.
+
+  A table of mean, median, 95th percentile and max steps is printed
+  along with how often the solver ran out of steps.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := RunArena(os.Stdout); err != nil {
+			fmt.Println(err)
+			os.Exit(-1)
+		}
+	},
+}
+
+func init() {
+	arenaCmd.Flags().Int("trials", 100, "times to solve each pairing")
+	arenaCmd.Flags().StringSlice("sizes", []string{"15x10"}, "maze sizes to run, as WIDTHxHEIGHT")
+	arenaCmd.Flags().String("format", "table", "output format. table, csv or json")
+
+	viper.BindPFlag("trials", arenaCmd.Flags().Lookup("trials"))
+	viper.BindPFlag("sizes", arenaCmd.Flags().Lookup("sizes"))
+	viper.BindPFlag("format", arenaCmd.Flags().Lookup("format"))
+	RootCmd.AddCommand(arenaCmd)
+}
+
+// ArenaResult summarizes one generator/solver/size pairing.
+// Step statistics only cover the runs where the treasure was found.
+type ArenaResult struct {
+	Generator   string  `json:"generator"`
+	Solver      string  `json:"solver"`
+	Width       int     `json:"width"`
+	Height      int     `json:"height"`
+	Trials      int     `json:"trials"`
+	Seed        int64   `json:"seed"`
+	Failures    int     `json:"failures"`
+	FailureRate float64 `json:"failureRate"`
+	Mean        float64 `json:"mean"`
+	Median      int     `json:"median"`
+	P95         int     `json:"p95"`
+	Max         int     `json:"max"`
+}
+
+// Run the full arena and write the results to w in the configured format.
+func RunArena(w io.Writer) error {
+	sizes, err := parseSizes(viper.GetStringSlice("sizes"))
+	if err != nil {
+		return err
+	}
+	trials := viper.GetInt("trials")
+	maxSteps := viper.GetInt("max-steps")
+	// Trial N of every pairing generates its maze from seed --seed + N, and seeds
+	// the solver from it too, so every solver faces exactly the same mazes.
+	seed := viper.GetInt64("seed")
+
+	results := []ArenaResult{}
+	for _, size := range sizes {
+		for _, reg := range generators.List() {
+			g, err := reg.New(nil)
+			if err != nil {
+				return err
+			}
+			for _, s := range solvers.List() {
+				scores := []int{}
+				for i := 0; i < trials; i++ {
+					trialSeed := seed + int64(i)
+					m := g.Generate(size.X, size.Y, newRand(trialSeed))
+					m.MaxSteps = maxSteps
+					m.Seed = trialSeed
+					if steps, err := solvers.Solve(m, s.New(solverRand(trialSeed)), nil); err == nil {
+						scores = append(scores, steps)
+					} else if err != mazelib.ErrOutOfSteps {
+						return fmt.Errorf("%s vs %s: %v", reg.Name, s.Name, err)
+					}
+				}
+				r := summarize(reg.Name, s.Name, size, trials, scores)
+				r.Seed = seed
+				results = append(results, r)
+			}
+		}
+	}
+
+	switch viper.GetString("format") {
+	case "table":
+		return writeArenaTable(w, results)
+	case "csv":
+		return writeArenaCSV(w, results)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+	return fmt.Errorf("unknown format %q", viper.GetString("format"))
+}
+
+func parseSizes(in []string) ([]mazelib.Coordinate, error) {
+	sizes := []mazelib.Coordinate{}
+	for _, s := range in {
+		parts := strings.Split(strings.ToLower(s), "x")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid size %q, expected WIDTHxHEIGHT", s)
+		}
+		w, errW := strconv.Atoi(parts[0])
+		h, errH := strconv.Atoi(parts[1])
+		if errW != nil || errH != nil || w < 1 || h < 1 || w*h < 2 {
+			return nil, fmt.Errorf("invalid size %q, expected WIDTHxHEIGHT", s)
+		}
+		sizes = append(sizes, mazelib.Coordinate{X: w, Y: h})
+	}
+	return sizes, nil
+}
+
+func summarize(generator, solver string, size mazelib.Coordinate, trials int, scores []int) ArenaResult {
+	r := ArenaResult{
+		Generator: generator,
+		Solver:    solver,
+		Width:     size.X,
+		Height:    size.Y,
+		Trials:    trials,
+		Failures:  trials - len(scores),
+	}
+	if trials > 0 {
+		r.FailureRate = float64(r.Failures) / float64(trials)
+	}
+	if len(scores) == 0 {
+		return r
+	}
+	sort.Ints(scores)
+	total := 0
+	for _, s := range scores {
+		total += s
+	}
+	r.Mean = float64(total) / float64(len(scores))
+	r.Median = percentile(scores, 0.5)
+	r.P95 = percentile(scores, 0.95)
+	r.Max = scores[len(scores)-1]
+	return r
+}
+
+// Nearest-rank percentile of already sorted scores.
+func percentile(sorted []int, p float64) int {
+	rank := int(math.Ceil(p * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func writeArenaTable(w io.Writer, results []ArenaResult) error {
+	if len(results) > 0 {
+		fmt.Fprintln(w, "Using seed", results[0].Seed)
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "generator\tsolver\tsize\ttrials\tmean\tmedian\tp95\tmax\tfailed\t")
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%s\t%dx%d\t%d\t%.1f\t%d\t%d\t%d\t%.1f%%\t\n",
+			r.Generator, r.Solver, r.Width, r.Height, r.Trials,
+			r.Mean, r.Median, r.P95, r.Max, r.FailureRate*100)
+	}
+	return tw.Flush()
+}
+
+func writeArenaCSV(w io.Writer, results []ArenaResult) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"generator", "solver", "width", "height", "trials", "seed", "failures", "failure_rate", "mean", "median", "p95", "max"})
+	for _, r := range results {
+		cw.Write([]string{
+			r.Generator, r.Solver,
+			strconv.Itoa(r.Width), strconv.Itoa(r.Height),
+			strconv.Itoa(r.Trials), strconv.FormatInt(r.Seed, 10), strconv.Itoa(r.Failures),
+			strconv.FormatFloat(r.FailureRate, 'f', 4, 64),
+			strconv.FormatFloat(r.Mean, 'f', 2, 64),
+			strconv.Itoa(r.Median), strconv.Itoa(r.P95), strconv.Itoa(r.Max),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
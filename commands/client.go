@@ -0,0 +1,132 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golangchallenge/gc6/mazelib"
+)
+
+// Client talks to a daedalus server on behalf of Icarus.
+// BaseURL may include a path prefix, e.g. http://host:8013/labyrinth
+type Client struct {
+	BaseURL string
+	HTTP    *http.Client
+
+	// The session daedalus assigned us on our first awakening.
+	// Sent with every request so our mazes and scores are kept apart from other clients.
+	Session string
+}
+
+// Create a client for the daedalus at baseURL with sensible timeouts.
+func NewClient(baseURL string) *Client {
+	return &Client{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		HTTP:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Make a call to the laybrinth server (daedalus) that icarus is ready to wake up
+func (c *Client) Awake() (mazelib.Survey, error) {
+	r, err := c.get("/awake")
+	if err != nil {
+		return mazelib.Survey{}, err
+	}
+	if r.Error {
+		return r.Survey, errors.New(r.Message)
+	}
+	if r.Session != "" {
+		c.Session = r.Session
+	}
+	return r.Survey, nil
+}
+
+// Make a call to the laybrinth server (daedalus)
+// to move Icarus a given direction
+// Returns ErrVictory once the treasure is found and ErrOutOfSteps if Icarus gave up.
+func (c *Client) Move(direction string) (mazelib.Survey, error) {
+	if direction != "left" && direction != "right" && direction != "up" && direction != "down" {
+		return mazelib.Survey{}, errors.New("invalid direction")
+	}
+
+	r, err := c.get("/move/" + direction)
+	if err != nil {
+		return mazelib.Survey{}, err
+	}
+
+	switch {
+	case r.Victory:
+		fmt.Println(r.Message)
+		return r.Survey, mazelib.ErrVictory
+	case r.GaveUp:
+		fmt.Println(r.Message)
+		return r.Survey, mazelib.ErrOutOfSteps
+	case r.Error:
+		return r.Survey, errors.New(r.Message)
+	}
+	return r.Survey, nil
+}
+
+// Tell daedalus we are done solving mazes.
+func (c *Client) Done() error {
+	r, err := c.get("/done")
+	if err != nil {
+		return err
+	}
+	if r.Error {
+		return errors.New(r.Message)
+	}
+	return nil
+}
+
+// utility function to wrap making requests to the daedalus server
+func (c *Client) get(path string) (mazelib.Reply, error) {
+	request, err := http.NewRequest("GET", c.BaseURL+path, nil)
+	if err != nil {
+		return mazelib.Reply{}, err
+	}
+	if c.Session != "" {
+		request.Header.Set(SessionHeader, c.Session)
+	}
+	response, err := c.HTTP.Do(request)
+	if err != nil {
+		return mazelib.Reply{}, err
+	}
+	defer response.Body.Close()
+	contents, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return mazelib.Reply{}, err
+	}
+	r, err := ToReply(contents)
+	if err != nil {
+		return r, fmt.Errorf("bad reply from daedalus (%s): %v", response.Status, err)
+	}
+	return r, nil
+}
+
+// Handling a JSON response and unmarshalling it into a reply struct
+func ToReply(in []byte) (mazelib.Reply, error) {
+	res := mazelib.Reply{}
+	err := json.Unmarshal(in, &res)
+	return res, err
+}
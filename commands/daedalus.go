@@ -16,11 +16,16 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"math/rand"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -30,15 +35,6 @@ import (
 	"github.com/spf13/viper"
 )
 
-// Tracking the current maze being solved
-
-// WARNING: This approach is not safe for concurrent use
-// This server is only intended to have a single client at a time
-// We would need a different and more complex approach if we wanted
-// concurrent connections than these simple package variables
-var currentMaze *mazelib.Maze
-var scores []int
-
 // Defining the daedalus command.
 // This will be called as 'laybrinth daedalus'
 var daedalusCmd = &cobra.Command{
@@ -48,89 +44,262 @@ var daedalusCmd = &cobra.Command{
 	Long: `Daedalus's job is to create a challenging Labyrinth for his opponent
   Icarus to solve.
 
-  Daedalus runs a server which Icarus clients can connect to to solve laybrinths.`,
+  Daedalus runs a server which Icarus clients can connect to to solve laybrinths.
+  It keeps serving, however many clients come and go, until interrupted.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		RunServer()
+		// Even when ctrl+c is pressed the server shuts down cleanly
+		// and prints out the results prior to exiting.
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		fmt.Println("Daedalus using seed", viper.GetInt64("seed"))
+		if _, err := RunServer(ctx); err != nil {
+			fmt.Println(err)
+			os.Exit(-1)
+		}
 	},
 }
 
 func init() {
-	rand.Seed(time.Now().UTC().UnixNano()) // need to initialize the seed
 	gin.SetMode(gin.ReleaseMode)
 
-	daedalusCmd.Flags().IntP("width", "w", 15, "width of the laybrinth")
-	daedalusCmd.Flags().IntP("height", "t", 10, "height of the laybrinth")
-
-	// Bind viper to these flags so viper can also read them from config, env, etc.
-	viper.SetDefault("width", 15)
-	viper.SetDefault("height", 10)
-	viper.BindPFlag("width", daedalusCmd.Flags().Lookup("width"))
-	viper.BindPFlag("height", daedalusCmd.Flags().Lookup("height"))
+	// The size of the laybrinth comes from the root --width and --height flags.
 	RootCmd.AddCommand(daedalusCmd)
 }
 
-// Runs the web server
-func RunServer() {
-	// Adding handling so that even when ctrl+c is pressed we still print
-	// out the results prior to exiting.
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt)
+// Server is a Daedalus that can be embedded in another program.
+// Every Icarus client gets its own session when it first wakes up.
+// Each session tracks the maze being solved and the scores for that client,
+// so many clients can be served at the same time.
+type Server struct {
+	sessions *sessionStore
+
+	// Results of every session that has ended.
+	mu       sync.Mutex
+	finished []SessionResult
+
+	// If set, the server stops once the last active session has ended.
+	// Otherwise it keeps serving until its context is cancelled.
+	StopWhenIdle bool
+
+	// Closed once the last active session has ended, if StopWhenIdle is set.
+	done     chan struct{}
+	doneOnce sync.Once
+
+	// Seeds for each new maze, starting at --seed.
+	seeds *seedSequence
+
+	// Closed once the server is listening. addr is only valid after that.
+	ready chan struct{}
+	addr  string
+}
+
+func NewServer() *Server {
+	return &Server{
+		sessions: newSessionStore(),
+		done:     make(chan struct{}),
+		ready:    make(chan struct{}),
+		seeds:    newSeedSequence(viper.GetInt64("seed")),
+	}
+}
+
+// Runs the web server on the configured port until ctx is cancelled.
+func RunServer(ctx context.Context) ([]SessionResult, error) {
+	return NewServer().Run(ctx, ":"+viper.GetString("port"))
+}
+
+// Runs the web server on addr until ctx is cancelled or, with StopWhenIdle,
+// the last Icarus says he is done.
+// Sessions still active at that point are ended as well,
+// and the results of every session are returned.
+// addr may use port 0, in which case Addr reports the port actually chosen.
+func (d *Server) Run(ctx context.Context, addr string) ([]SessionResult, error) {
+	// Catch a bad --generator or --maze before any Icarus shows up
+	if _, err := createMaze(0); err != nil {
+		return nil, err
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	d.addr = ln.Addr().String()
+	close(d.ready)
+
+	srv := &http.Server{Handler: d.Handler()}
+
+	// Forget about clients that have gone quiet, but keep their results.
+	stopReaping := make(chan struct{})
+	defer close(stopReaping)
+	go d.sessions.reap(viper.GetDuration("session-timeout"), stopReaping, func(s *session) {
+		fmt.Printf("Session %s expired\n", s.id)
+		d.finish(s)
+		d.stopIfIdle()
+	})
+
+	errs := make(chan error, 1)
 	go func() {
-		<-c
-		printResults()
-		os.Exit(1)
+		errs <- srv.Serve(ln)
 	}()
 
+	select {
+	case err := <-errs:
+		return d.Results(), err
+	case <-ctx.Done():
+	case <-d.done:
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = srv.Shutdown(shutdownCtx)
+
+	for _, s := range d.sessions.all() {
+		d.sessions.remove(s.id)
+		d.finish(s)
+	}
+	return d.Results(), err
+}
+
+// The routes Icarus talks to.
+func (d *Server) Handler() http.Handler {
 	// Using gin-gonic/gin to handle our routing
 	r := gin.New()
 
 	v1 := r.Group("/")
 	{
-		v1.GET("/awake", GetStartingPoint)
-		v1.GET("/move/:direction", MoveDirection)
-		v1.GET("/done", End)
+		v1.GET("/awake", d.GetStartingPoint)
+		v1.GET("/move/:direction", d.MoveDirection)
+		v1.GET("/done", d.End)
+		v1.GET("/healthz", Healthz)
 	}
+	return r
+}
+
+// Ready is closed once the server is accepting connections.
+func (d *Server) Ready() <-chan struct{} {
+	return d.ready
+}
+
+// The address the server is listening on. Only valid once Ready is closed.
+func (d *Server) Addr() string {
+	<-d.ready
+	return d.addr
+}
+
+// Lets clients and orchestration check that daedalus is up.
+func Healthz(c *gin.Context) {
+	c.String(http.StatusOK, "ok")
+}
 
-	r.Run(":" + viper.GetString("port"))
+// Done is closed once the last active session has ended, if StopWhenIdle is set.
+func (d *Server) Done() <-chan struct{} {
+	return d.done
+}
+
+// Results of every session that has ended so far.
+func (d *Server) Results() []SessionResult {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]SessionResult(nil), d.finished...)
+}
+
+// Print and record the results of a session that has been removed from the store.
+func (d *Server) finish(s *session) {
+	s.Lock()
+	res := s.result()
+	s.Unlock()
+	printResults(res)
+	d.mu.Lock()
+	d.finished = append(d.finished, res)
+	d.mu.Unlock()
 }
 
 // Ends a session and prints the results.
 // Called by Icarus when he has reached
 //   the number of times he wants to solve the laybrinth.
-func End(c *gin.Context) {
-	printResults()
-	os.Exit(0)
+// With StopWhenIdle, once the last session has ended the server is signalled to stop.
+func (d *Server) End(c *gin.Context) {
+	s := d.sessions.remove(c.Request.Header.Get(SessionHeader))
+	if s == nil {
+		c.JSON(http.StatusNotFound, mazelib.Reply{Error: true, Message: "unknown session"})
+		return
+	}
+	d.finish(s)
+	c.JSON(http.StatusOK, mazelib.Reply{Session: s.id})
+	d.stopIfIdle()
+}
+
+// With StopWhenIdle, signal the server to stop once no sessions are left,
+// whether the last one ended or expired.
+func (d *Server) stopIfIdle() {
+	if d.StopWhenIdle && d.sessions.len() == 0 {
+		d.doneOnce.Do(func() { close(d.done) })
+	}
 }
 
 // initializes a new maze and places Icarus in his awakening location
-func GetStartingPoint(c *gin.Context) {
-	initializeMaze()
-	startRoom, err := currentMaze.Discover(currentMaze.Icarus())
+// A client that does not send a known session id is given a new session.
+func (d *Server) GetStartingPoint(c *gin.Context) {
+	s := d.sessions.get(c.Request.Header.Get(SessionHeader))
+	if s == nil {
+		s = d.sessions.create()
+	}
+	s.Lock()
+	defer s.Unlock()
+
+	m, err := createMaze(d.seeds.Next())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, mazelib.Reply{Error: true, Message: err.Error()})
+		return
+	}
+	s.maze = m
+	startRoom, err := s.maze.Discover(s.maze.Icarus())
 	if err != nil {
 		fmt.Println("Icarus is outside of the maze. This shouldn't ever happen")
 		fmt.Println(err)
-		os.Exit(-1)
+		c.JSON(http.StatusInternalServerError, mazelib.Reply{Error: true, Message: err.Error()})
+		return
 	}
-	mazelib.PrintMaze(currentMaze)
-	c.JSON(http.StatusOK, mazelib.Reply{Survey: startRoom})
+	printMaze(s.maze)
+	c.Header(SessionHeader, s.id)
+	c.JSON(http.StatusOK, mazelib.Reply{Survey: startRoom, Session: s.id, Seed: m.Seed})
 }
 
 // The API response to the /move/:direction address
-func MoveDirection(c *gin.Context) {
-	var err error
-
-	switch c.Param("direction") {
-	case "left":
-		err = currentMaze.MoveLeft()
-	case "right":
-		err = currentMaze.MoveRight()
-	case "down":
-		err = currentMaze.MoveDown()
-	case "up":
-		err = currentMaze.MoveUp()
-	}
+func (d *Server) MoveDirection(c *gin.Context) {
 	var r mazelib.Reply
 
+	s := d.sessions.get(c.Request.Header.Get(SessionHeader))
+	if s == nil {
+		r.Error = true
+		r.Message = "unknown session"
+		c.JSON(http.StatusNotFound, r)
+		return
+	}
+	s.Lock()
+	defer s.Unlock()
+	r.Session = s.id
+
+	if s.maze == nil {
+		r.Error = true
+		r.Message = "Icarus is not awake"
+		c.JSON(409, r)
+		return
+	}
+	r.Seed = s.maze.Seed
+
+	err := s.maze.Move(c.Param("direction"))
+
+	if err == mazelib.ErrOutOfSteps {
+		// Icarus has wandered too long. Count it as a failed run and
+		// put him back to sleep until he asks for a new maze.
+		s.failures++
+		r.GaveUp = true
+		r.Message = fmt.Sprintf("Gave up after %d steps (seed %d) \n", s.maze.StepsTaken, r.Seed)
+		s.maze = nil
+		c.JSON(http.StatusOK, r)
+		return
+	}
+
 	if err != nil {
 		r.Error = true
 		r.Message = err.Error()
@@ -138,51 +307,123 @@ func MoveDirection(c *gin.Context) {
 		return
 	}
 
-	s, e := currentMaze.LookAround()
+	survey, e := s.maze.LookAround()
 
 	if e != nil {
 		if e == mazelib.ErrVictory {
-			scores = append(scores, currentMaze.StepsTaken)
+			s.scores = append(s.scores, s.maze.StepsTaken)
 			r.Victory = true
-			r.Message = fmt.Sprintf("Victory achieved in %d steps \n", currentMaze.StepsTaken)
+			r.Message = fmt.Sprintf("Victory achieved in %d steps (seed %d) \n", s.maze.StepsTaken, r.Seed)
 		} else {
 			r.Error = true
-			r.Message = err.Error()
+			r.Message = e.Error()
 		}
 	}
 
-	r.Survey = s
+	r.Survey = survey
 
 	c.JSON(http.StatusOK, r)
 }
 
-func initializeMaze() {
-	currentMaze = createMaze()
+// PrintMaze writes straight to stdout, so serialize it
+// to keep concurrent sessions from interleaving their output.
+var printMu sync.Mutex
+
+func printMaze(m *mazelib.Maze) {
+	printMu.Lock()
+	defer printMu.Unlock()
+	mazelib.PrintMaze(m)
 }
 
-// Print to the terminal the average steps to solution for the current session
-func printResults() {
-	fmt.Printf("Labyrinth solved %d times with an avg of %d steps\n", len(scores), mazelib.AvgScores(scores))
+// Print to the terminal the average steps to solution for the given session
+// Runs where Icarus gave up are reported separately and do not count towards the average.
+func printResults(r SessionResult) {
+	fmt.Printf("Session %s: Labyrinth solved %d times with an avg of %d steps\n", r.ID, len(r.Scores), mazelib.AvgScores(r.Scores))
+	if r.Failures > 0 {
+		fmt.Printf("Session %s: Icarus gave up %d times after running out of steps\n", r.ID, r.Failures)
+	}
 }
 
 // Creates a maze without any walls
 // Good starting point for additive algorithms
-func EmptyMaze() *mazelib.Maze {
+func EmptyMaze(rng *rand.Rand) *mazelib.Maze {
 	ySize := viper.GetInt("height")
 	xSize := viper.GetInt("width")
-	return mazelib.EmptyMaze(xSize, ySize)
+	return mazelib.EmptyMaze(xSize, ySize, rng)
 }
 
 // Creates a maze with all walls
 // Good starting point for subtractive algorithms
-func FullMaze() *mazelib.Maze {
+func FullMaze(rng *rand.Rand) *mazelib.Maze {
 	ySize := viper.GetInt("height")
 	xSize := viper.GetInt("width")
-	return mazelib.FullMaze(xSize, ySize)
+	return mazelib.FullMaze(xSize, ySize, rng)
 }
 
-func createMaze() *mazelib.Maze {
-	ySize := viper.GetInt("height")
-	xSize := viper.GetInt("width")
-	return generators.DepthFirst(xSize, ySize, viper.GetString("bias"))
+// The generator picked with --generator.
+// Every generator option that was set, with a flag or in config, is passed
+// on, e.g. --bias. Options the generator doesn't understand are an error,
+// rather than being quietly ignored.
+func newGenerator() (generators.Generator, error) {
+	opts := generators.Options{}
+	for _, r := range generators.List() {
+		for opt := range r.Options {
+			if viper.IsSet(opt) {
+				opts[opt] = viper.GetString(opt)
+			}
+		}
+	}
+	return generators.New(viper.GetString("generator"), opts)
+}
+
+// Generate a maze with the configured generator, shaped by --mask and braided by --braid if given.
+// The same seed always gives the same maze.
+// If a maze file was given with --maze, that maze is used every time instead.
+// Daedalus refuses to serve a maze that fails validation.
+func createMaze(seed int64) (*mazelib.Maze, error) {
+	var m *mazelib.Maze
+	if path := viper.GetString("maze"); path != "" {
+		var err error
+		if m, err = loadMaze(path); err != nil {
+			return nil, err
+		}
+	} else {
+		g, err := newGenerator()
+		if err != nil {
+			return nil, err
+		}
+		if path := viper.GetString("mask"); path != "" {
+			k, err := loadMask(path)
+			if err != nil {
+				return nil, err
+			}
+			if g, err = generators.Masked(g, k); err != nil {
+				return nil, fmt.Errorf("%s: %v", path, err)
+			}
+		}
+		ySize := viper.GetInt("height")
+		xSize := viper.GetInt("width")
+		rng := newRand(seed)
+		m = g.Generate(xSize, ySize, rng)
+		generators.Braid(m, viper.GetFloat64("braid"), rng)
+		m.Generator = viper.GetString("generator")
+		m.Seed = seed
+	}
+	if err := validationError(m.Validate()); err != nil {
+		return nil, err
+	}
+	m.MaxSteps = viper.GetInt("max-steps")
+	return m, nil
+}
+
+// Combine every problem Validate found into a single error.
+func validationError(errs []mazelib.ValidationError) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Error()
+	}
+	return errors.New("invalid maze: " + strings.Join(msgs, "; "))
 }
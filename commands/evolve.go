@@ -0,0 +1,116 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/golangchallenge/gc6/generators"
+	"github.com/golangchallenge/gc6/mazelib"
+	"github.com/golangchallenge/gc6/solvers"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Defining the evolve command.
+// This will be called as 'laybrinth evolve'
+var evolveCmd = &cobra.Command{
+	Use:   "evolve",
+	Short: "Search for a laybrinth the configured solver finds hard",
+	Long: `Start from a laybrinth made with the configured generator, or the one given
+  with --maze, and keep moving its walls around, keeping every change that makes
+  the configured solver take at least as many steps on average over --runs runs.
+  The start and treasure stay put and the maze stays valid throughout.
+
+  Progress is printed to stderr as the best score improves. The final maze is
+  saved as JSON like 'labyrinth generate', so it can be served with
+  'labyrinth daedalus --maze'. Without --out the JSON is written to stdout.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		out, _ := cmd.Flags().GetString("out")
+		runs, _ := cmd.Flags().GetInt("runs")
+		budget, _ := cmd.Flags().GetDuration("budget")
+		rounds, _ := cmd.Flags().GetInt("rounds")
+		if err := runEvolve(out, runs, budget, rounds); err != nil {
+			fmt.Println(err)
+			os.Exit(-1)
+		}
+	},
+}
+
+func init() {
+	evolveCmd.Flags().StringP("out", "o", "", "file to write the maze to")
+	evolveCmd.Flags().Int("runs", 20, "times the solver runs through each maze to score it")
+	evolveCmd.Flags().Duration("budget", 10*time.Second, "how long to search for. 0 for no limit")
+	evolveCmd.Flags().Int("rounds", 0, "stop after this many changes have been tried. 0 for no limit. Set this and --seed for repeatable results")
+	RootCmd.AddCommand(evolveCmd)
+}
+
+func runEvolve(out string, runs int, budget time.Duration, rounds int) error {
+	seed := viper.GetInt64("seed")
+	m, err := createMaze(seed)
+	if err != nil {
+		return err
+	}
+	solver := viper.GetString("solver")
+	reg, ok := solvers.Lookup(solver)
+	if !ok {
+		_, err := solvers.New(solver, nil)
+		return err
+	}
+
+	e := generators.Evolver{
+		Solver: reg.New,
+		Runs:   runs,
+		Budget: budget,
+		Rounds: rounds,
+		Progress: func(p generators.EvolveProgress) {
+			fmt.Fprintf(os.Stderr, "%6.1fs  round %-8d kept %-6d mean steps %.1f (started at %.1f)\n",
+				p.Elapsed.Seconds(), p.Round, p.Accepted, p.Best, p.Initial)
+		},
+	}
+	if _, err := e.Evolve(m, solverRand(seed)); err != nil {
+		return err
+	}
+	if err := validationError(m.Validate()); err != nil {
+		return err
+	}
+	// The maze is no longer what its generator made from its seed.
+	// The seed only still describes it if a round limit alone ended the search.
+	from := m.Generator
+	if from == "" {
+		from = viper.GetString("maze")
+	}
+	m.Generator = fmt.Sprintf("evolve(%s, solver %s)", from, solver)
+	if budget > 0 {
+		m.Seed = 0
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if out == "" || out == "-" {
+		_, err = fmt.Printf("%s\n", data)
+		return err
+	}
+	mazelib.PrintMaze(m)
+	fmt.Println("Saved to", out)
+	return ioutil.WriteFile(out, append(data, '\n'), 0644)
+}
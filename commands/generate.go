@@ -0,0 +1,106 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/golangchallenge/gc6/mazelib"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Defining the generate command.
+// This will be called as 'laybrinth generate'
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate a single laybrinth and save it as JSON",
+	Long: `Generate a laybrinth with the configured generator, size and seed
+  and save it as JSON, so it can later be served with 'labyrinth daedalus --maze'.
+
+  Without --out the JSON is written to stdout.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runGenerate(viper.GetString("out")); err != nil {
+			fmt.Println(err)
+			os.Exit(-1)
+		}
+	},
+}
+
+func init() {
+	generateCmd.Flags().StringP("out", "o", "", "file to write the maze to")
+	viper.BindPFlag("out", generateCmd.Flags().Lookup("out"))
+	RootCmd.AddCommand(generateCmd)
+}
+
+func runGenerate(out string) error {
+	m, err := createMaze(viper.GetInt64("seed"))
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if out == "" || out == "-" {
+		_, err = fmt.Printf("%s\n", data)
+		return err
+	}
+	mazelib.PrintMaze(m)
+	fmt.Println("Saved to", out)
+	return ioutil.WriteFile(out, append(data, '\n'), 0644)
+}
+
+// Read a maze saved by 'labyrinth generate'.
+func loadMaze(path string) (*mazelib.Maze, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m := &mazelib.Maze{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return m, nil
+}
+
+// Read a mask for --mask. PNG files are read as black and white images,
+// anything else as ASCII art. See mazelib.ParseMask and mazelib.MaskFromImage.
+func loadMask(path string) (*mazelib.Mask, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if strings.EqualFold(filepath.Ext(path), ".png") {
+		img, err := png.Decode(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		return mazelib.MaskFromImage(img), nil
+	}
+	k, err := mazelib.ParseMask(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return k, nil
+}
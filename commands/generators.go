@@ -0,0 +1,55 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/golangchallenge/gc6/generators"
+	"github.com/spf13/cobra"
+)
+
+// Defining the generators command.
+// This will be called as 'laybrinth generators'
+var generatorsCmd = &cobra.Command{
+	Use:   "generators",
+	Short: "Maze generators daedalus can use",
+}
+
+// This will be called as 'laybrinth generators list'
+var generatorsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the available maze generators and their options",
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, r := range generators.List() {
+			fmt.Printf("%-10s %s\n", r.Name, r.Description)
+			opts := []string{}
+			for opt := range r.Options {
+				opts = append(opts, opt)
+			}
+			sort.Strings(opts)
+			for _, opt := range opts {
+				fmt.Printf("%-10s   --%s: %s\n", "", opt, r.Options[opt])
+			}
+		}
+	},
+}
+
+func init() {
+	generatorsCmd.AddCommand(generatorsListCmd)
+	RootCmd.AddCommand(generatorsCmd)
+}
@@ -16,11 +16,8 @@
 package commands
 
 import (
-	"encoding/json"
-	"errors"
 	"fmt"
-	"io/ioutil"
-	"net/http"
+	"os"
 
 	"github.com/golangchallenge/gc6/mazelib"
 	"github.com/golangchallenge/gc6/solvers"
@@ -41,7 +38,14 @@ var icarusCmd = &cobra.Command{
 
   Icarus can connect to a Daedalus and solve many laybrinths at a time.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		RunIcarus()
+		t, err := newTransport(serverURL())
+		if err == nil {
+			err = RunIcarus(t)
+		}
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(-1)
+		}
 	},
 }
 
@@ -49,86 +53,54 @@ func init() {
 	RootCmd.AddCommand(icarusCmd)
 }
 
-func RunIcarus() {
-	// Run the solver as many times as the user desires.
-	fmt.Println("Solving", viper.GetInt("times"), "times")
-	for x := 0; x < viper.GetInt("times"); x++ {
-		solveMaze()
+// The daedalus Icarus should talk to.
+// Defaults to the local daedalus on the configured port.
+func serverURL() string {
+	if url := viper.GetString("server"); url != "" {
+		return url
 	}
-
-	// Once we have solved the maze the required times, tell daedalus we are done
-	makeRequest("http://127.0.0.1:" + viper.GetString("port") + "/done")
+	return "http://127.0.0.1:" + viper.GetString("port")
 }
 
-// Make a call to the laybrinth server (daedalus) that icarus is ready to wake up
-func awake() mazelib.Survey {
-	contents, err := makeRequest("http://127.0.0.1:" + viper.GetString("port") + "/awake")
-	if err != nil {
-		fmt.Println(err)
+// Solve mazes served by the daedalus behind the given transport.
+func RunIcarus(c Transport) error {
+	// Catch a bad --solver before waking up
+	if _, err := solvers.New(viper.GetString("solver"), newRand(0)); err != nil {
+		return err
 	}
-	r := ToReply(contents)
-	return r.Survey
-}
-
-// Make a call to the laybrinth server (daedalus)
-// to move Icarus a given direction
-// Will be used heavily by solveMaze
-func Move(direction string) (mazelib.Survey, error) {
-	if direction == "left" || direction == "right" || direction == "up" || direction == "down" {
-
-		contents, err := makeRequest("http://127.0.0.1:" + viper.GetString("port") + "/move/" + direction)
-		if err != nil {
-			return mazelib.Survey{}, err
-		}
 
-		rep := ToReply(contents)
-		if rep.Victory == true {
-			fmt.Println(rep.Message)
-			return rep.Survey, mazelib.ErrVictory
-		} else {
-			return rep.Survey, errors.New(rep.Message)
+	// Run the solver as many times as the user desires.
+	// Solver for run N is seeded from --seed + N, as daedalus seeds maze N.
+	seeds := newSeedSequence(viper.GetInt64("seed"))
+	fmt.Println("Solving", viper.GetInt("times"), "times with seed", viper.GetInt64("seed"))
+	for x := 0; x < viper.GetInt("times"); x++ {
+		if err := solveMaze(c, seeds.Next()); err != nil {
+			return err
 		}
 	}
 
-	return mazelib.Survey{}, errors.New("invalid direction")
+	// Once we have solved the maze the required times, tell daedalus we are done
+	return c.Done()
 }
 
-// utility function to wrap making requests to the daedalus server
-func makeRequest(url string) ([]byte, error) {
-	response, err := http.Get(url)
+func solveMaze(c Transport, seed int64) error {
+
+	solver, err := solvers.New(viper.GetString("solver"), solverRand(seed))
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer response.Body.Close()
-	contents, err := ioutil.ReadAll(response.Body)
+	current, err := c.Awake()
 	if err != nil {
-		return nil, err
-	}
-	return contents, nil
-}
-
-// Handling a JSON response and unmarshalling it into a reply struct
-func ToReply(in []byte) mazelib.Reply {
-	res := &mazelib.Reply{}
-	json.Unmarshal(in, &res)
-	return *res
-}
-
-func solveMaze() {
-
-	var solver solvers.MazeSolver
-	if viper.GetBool("mouse") {
-		solver = solvers.NewMouse()
-	} else {
-		solver = solvers.NewDFS()
+		return err
 	}
-	current := awake()
-	var err error
 	for {
 		dir := solver.Step(current)
-		current, err = Move(dir)
-		if err == mazelib.ErrVictory || err.Error() != "" {
-			break
+		current, err = c.Move(dir)
+		if err == mazelib.ErrVictory || err == mazelib.ErrOutOfSteps {
+			return nil
+		}
+		if err != nil {
+			return err
 		}
 	}
 }
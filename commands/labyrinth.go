@@ -20,8 +20,10 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -52,13 +54,51 @@ if there is a wall or not to the top, right, bottom and left. He takes
 one step and then can discover if his new cell has walls on each of
 the four sides.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		go RunServer()
-
-		// give server time to start before sending a request.
-		// There's a better way to do this, but I'm lazy and this is just for fun.
-		time.Sleep(1 * time.Second)
-
-		RunIcarus()
+		t, err := newTransport("")
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(-1)
+		}
+		if _, ok := t.(*LocalTransport); ok {
+			// No server needed, Icarus solves everything in-process.
+			if err := RunIcarus(t); err != nil {
+				fmt.Println(err)
+				os.Exit(-1)
+			}
+			return
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		// Daedalus picks any free port on loopback, and Icarus is pointed at
+		// it as soon as it is listening, and stops once Icarus is done.
+		d := NewServer()
+		d.StopWhenIdle = true
+		errs := make(chan error, 1)
+		go func() {
+			_, err := d.Run(ctx, "127.0.0.1:0")
+			errs <- err
+		}()
+
+		select {
+		case <-d.Ready():
+		case err := <-errs:
+			fmt.Println(err)
+			os.Exit(-1)
+		}
+
+		if err := RunIcarus(NewClient("http://" + d.Addr())); err != nil {
+			fmt.Println(err)
+			// Icarus won't be saying he is done, so stop daedalus ourselves.
+			stop()
+		}
+
+		// Wait for daedalus to finish up before exiting
+		if err := <-errs; err != nil {
+			fmt.Println(err)
+			os.Exit(-1)
+		}
 	},
 }
 
@@ -69,23 +109,53 @@ func init() {
 	// by the indidual behaviors of icarus and daedalus
 	RootCmd.PersistentFlags().StringVar(&CfgFile, "config", "", "config file (default is $CWD/config.yaml)")
 	RootCmd.PersistentFlags().IntP("port", "p", 8013, "Port run on")
+	RootCmd.PersistentFlags().String("transport", "http", "How icarus reaches daedalus. http or local (in-process, no server)")
+	RootCmd.PersistentFlags().String("server", "", "URL of the daedalus for icarus to connect to (default is http://127.0.0.1:<port>)")
 	RootCmd.PersistentFlags().IntP("width", "x", 15, "width of the laybrinth")
 	RootCmd.PersistentFlags().IntP("height", "y", 10, "height of the laybrinth") // 'h' is used for help already
 	RootCmd.PersistentFlags().IntP("times", "t", 1, "times to solve the laybrinth")
 	RootCmd.PersistentFlags().IntP("max-steps", "m", 500, "Maximum steps before giving up")
-
-	RootCmd.PersistentFlags().Bool("mouse", false, "Use random mouse solver")
-	RootCmd.PersistentFlags().String("bias", "O", "Bias for maze generator. H,V,X,O or D")
+	RootCmd.PersistentFlags().Int64("seed", 0, "Seed for all randomness. Runs with the same seed are identical (default picks one from the clock)")
+	RootCmd.PersistentFlags().Duration("session-timeout", 5*time.Minute, "Idle time before a daedalus session is discarded")
+
+	RootCmd.PersistentFlags().String("solver", "dfs", "Maze solver to use. See 'labyrinth solvers list'")
+	RootCmd.PersistentFlags().String("generator", "dfs-o", "Maze generator to use. See 'labyrinth generators list'")
+	RootCmd.PersistentFlags().String("bias", "", "Bias for generators that take one, such as dfs and division. H, V, X or O (default none)")
+	RootCmd.PersistentFlags().String("corner", "", "Corner the binary-tree and sidewinder generators lean towards. NE, NW, SE or SW")
+	RootCmd.PersistentFlags().Float64("braid", 0, "Share of dead ends, from 0 to 1, to knock through after generating, adding loops")
+	RootCmd.PersistentFlags().String("policy", "", "How the growing-tree generator picks rooms, e.g. newest, random or newest:75,random:25")
+	RootCmd.PersistentFlags().String("min-chamber", "", "Smallest chamber the division generator will cut")
+	RootCmd.PersistentFlags().String("rooms", "", "Chance from 0 to 1 of the division generator leaving small chambers open")
+	RootCmd.PersistentFlags().String("room-size", "", "Largest chamber the division generator may leave open")
+	RootCmd.PersistentFlags().String("start", "", "Where generators that support it put the start. random, corner or center")
+	RootCmd.PersistentFlags().String("treasure", "", "Where generators that support it put the treasure. random, corner or farthest")
+	RootCmd.PersistentFlags().String("mask", "", "Shape generated mazes with this mask: ASCII art (X or # for solid rock) or a black and white PNG. Overrides width and height")
+	RootCmd.PersistentFlags().String("maze", "", "Serve the maze saved in this JSON file instead of generating new ones")
 
 	// Bind viper to these flags so viper can read flag values along with config, env, etc.
 	viper.BindPFlag("width", RootCmd.PersistentFlags().Lookup("width"))
 	viper.BindPFlag("height", RootCmd.PersistentFlags().Lookup("height"))
 	viper.BindPFlag("port", RootCmd.PersistentFlags().Lookup("port"))
+	viper.BindPFlag("server", RootCmd.PersistentFlags().Lookup("server"))
+	viper.BindPFlag("transport", RootCmd.PersistentFlags().Lookup("transport"))
 	viper.BindPFlag("times", RootCmd.PersistentFlags().Lookup("times"))
 	viper.BindPFlag("max-steps", RootCmd.PersistentFlags().Lookup("max-steps"))
+	viper.BindPFlag("seed", RootCmd.PersistentFlags().Lookup("seed"))
+	viper.BindPFlag("session-timeout", RootCmd.PersistentFlags().Lookup("session-timeout"))
 
-	viper.BindPFlag("mouse", RootCmd.PersistentFlags().Lookup("mouse"))
+	viper.BindPFlag("solver", RootCmd.PersistentFlags().Lookup("solver"))
+	viper.BindPFlag("generator", RootCmd.PersistentFlags().Lookup("generator"))
 	viper.BindPFlag("bias", RootCmd.PersistentFlags().Lookup("bias"))
+	viper.BindPFlag("corner", RootCmd.PersistentFlags().Lookup("corner"))
+	viper.BindPFlag("braid", RootCmd.PersistentFlags().Lookup("braid"))
+	viper.BindPFlag("policy", RootCmd.PersistentFlags().Lookup("policy"))
+	viper.BindPFlag("min-chamber", RootCmd.PersistentFlags().Lookup("min-chamber"))
+	viper.BindPFlag("rooms", RootCmd.PersistentFlags().Lookup("rooms"))
+	viper.BindPFlag("room-size", RootCmd.PersistentFlags().Lookup("room-size"))
+	viper.BindPFlag("start", RootCmd.PersistentFlags().Lookup("start"))
+	viper.BindPFlag("treasure", RootCmd.PersistentFlags().Lookup("treasure"))
+	viper.BindPFlag("mask", RootCmd.PersistentFlags().Lookup("mask"))
+	viper.BindPFlag("maze", RootCmd.PersistentFlags().Lookup("maze"))
 }
 
 // Read in config file and ENV variables if set.
@@ -104,6 +174,11 @@ func initConfig() {
 	if err := viper.ReadInConfig(); err == nil {
 		fmt.Println("Using config file:", viper.ConfigFileUsed())
 	}
+
+	// Pick a seed once, so daedalus and icarus agree on it when run together.
+	if viper.GetInt64("seed") == 0 {
+		viper.Set("seed", time.Now().UnixNano())
+	}
 }
 
 //Execute adds all child commands to the root command Labyrinth and sets flags appropriately.
@@ -0,0 +1,119 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/golangchallenge/gc6/mazelib"
+	"github.com/golangchallenge/gc6/render"
+	"github.com/golangchallenge/gc6/solvers"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Defining the render command.
+// This will be called as 'laybrinth render'
+// Its flags are read straight from the command, since names like "out"
+// are already bound in viper by other commands.
+var renderCmd = &cobra.Command{
+	Use:   "render",
+	Short: "Draw a laybrinth as an SVG or PNG image",
+	Long: `Draw the maze given with --maze, or a freshly generated one, as an image.
+  The format is picked from the extension of --out (.svg or .png).
+  Without --out an SVG is written to stdout.
+
+  With --solve the configured solver is run on the maze and its path drawn on top.
+  With --heatmap rooms are shaded by how often the solver visited them.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runRender(cmd); err != nil {
+			fmt.Println(err)
+			os.Exit(-1)
+		}
+	},
+}
+
+func init() {
+	renderCmd.Flags().StringP("out", "o", "", "file to write the image to, .svg or .png")
+	renderCmd.Flags().Int("cell-size", 50, "size of each room in pixels")
+	renderCmd.Flags().Bool("solve", false, "overlay the path taken by the solver")
+	renderCmd.Flags().Bool("heatmap", false, "shade rooms by how often the solver visited them")
+	renderCmd.Flags().Bool("markers", true, "mark the start and treasure")
+	RootCmd.AddCommand(renderCmd)
+}
+
+func runRender(cmd *cobra.Command) error {
+	out, _ := cmd.Flags().GetString("out")
+	cellSize, _ := cmd.Flags().GetInt("cell-size")
+	solve, _ := cmd.Flags().GetBool("solve")
+	heatmap, _ := cmd.Flags().GetBool("heatmap")
+	markers, _ := cmd.Flags().GetBool("markers")
+
+	var write func(io.Writer, *mazelib.Maze, render.Options) error
+	switch strings.ToLower(filepath.Ext(out)) {
+	case ".svg", "":
+		write = render.SVG
+	case ".png":
+		write = render.PNG
+	default:
+		return fmt.Errorf("don't know how to render %s, use .svg or .png", out)
+	}
+
+	seed := viper.GetInt64("seed")
+	m, err := createMaze(seed)
+	if err != nil {
+		return err
+	}
+	opts := render.Options{CellSize: cellSize, Markers: markers}
+
+	if solve || heatmap {
+		solver, err := solvers.New(viper.GetString("solver"), solverRand(seed))
+		if err != nil {
+			return err
+		}
+		x, y := m.Icarus()
+		trace := []mazelib.Coordinate{{X: x, Y: y}}
+		_, err = solvers.Solve(m, solver, func(x, y int) {
+			trace = append(trace, mazelib.Coordinate{X: x, Y: y})
+		})
+		if err != nil && err != mazelib.ErrOutOfSteps {
+			return err
+		}
+		if solve {
+			opts.Trace = trace
+		}
+		if heatmap {
+			opts.Heatmap = render.Visits(trace)
+		}
+	}
+
+	if out == "" {
+		return write(os.Stdout, m, opts)
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if err := write(f, m, opts); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
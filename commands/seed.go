@@ -0,0 +1,60 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// Every maze and every solver gets its own random source,
+// so a single run can be replayed exactly from its seed.
+func newRand(seed int64) *rand.Rand {
+	return rand.New(rand.NewSource(seed))
+}
+
+// The random source for whatever solves or studies the maze made from seed.
+// It is derived from the seed, so the run still replays exactly, but it is not
+// the generator's own stream: a solver seeded the same way as the generator
+// would draw the very numbers that carved the maze.
+func solverRand(seed int64) *rand.Rand {
+	// splitmix64's finalizer, so neighbouring seeds give unrelated streams
+	z := uint64(seed) + 0x9e3779b97f4a7c15
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	return newRand(int64(z ^ z>>31))
+}
+
+// seedSequence hands out consecutive seeds starting at a base seed.
+// Run N of a sequence started with --seed S can be replayed on its own
+// with --seed S+N --times 1.
+// It is safe for concurrent use.
+type seedSequence struct {
+	mu   sync.Mutex
+	next int64
+}
+
+func newSeedSequence(base int64) *seedSequence {
+	return &seedSequence{next: base}
+}
+
+func (s *seedSequence) Next() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seed := s.next
+	s.next++
+	return seed
+}
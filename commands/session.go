@@ -0,0 +1,161 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/golangchallenge/gc6/mazelib"
+)
+
+// Header used to carry the session id between Icarus and Daedalus.
+// The id is also returned in the body of the /awake reply.
+const SessionHeader = "X-Session-Id"
+
+// A session tracks the maze a single Icarus client is currently solving,
+// along with the scores for every maze it has solved so far
+// and the number of mazes it gave up on.
+type session struct {
+	sync.Mutex
+	id       string
+	maze     *mazelib.Maze
+	scores   []int
+	failures int
+	lastSeen time.Time
+}
+
+// SessionResult is the final tally for a single Icarus session.
+type SessionResult struct {
+	ID string
+	// Steps taken for every maze that was solved.
+	Scores []int
+	// Number of mazes Icarus gave up on.
+	Failures int
+}
+
+// Callers must hold the session lock.
+func (s *session) result() SessionResult {
+	return SessionResult{ID: s.id, Scores: append([]int(nil), s.scores...), Failures: s.failures}
+}
+
+// sessionStore holds every active session, keyed by id.
+// It is safe for concurrent use.
+type sessionStore struct {
+	sync.Mutex
+	sessions map[string]*session
+}
+
+func newSessionStore() *sessionStore {
+	return &sessionStore{sessions: map[string]*session{}}
+}
+
+// Create a new, empty session with a random id.
+func (st *sessionStore) create() *session {
+	s := &session{id: newSessionID(), lastSeen: time.Now()}
+	st.Lock()
+	st.sessions[s.id] = s
+	st.Unlock()
+	return s
+}
+
+// Look up a session by id, marking it as recently used.
+// Returns nil if no such session exists.
+func (st *sessionStore) get(id string) *session {
+	st.Lock()
+	defer st.Unlock()
+	s := st.sessions[id]
+	if s != nil {
+		s.lastSeen = time.Now()
+	}
+	return s
+}
+
+// Remove a session from the store, returning it.
+// Returns nil if no such session exists.
+func (st *sessionStore) remove(id string) *session {
+	st.Lock()
+	defer st.Unlock()
+	s := st.sessions[id]
+	delete(st.sessions, id)
+	return s
+}
+
+// Number of active sessions.
+func (st *sessionStore) len() int {
+	st.Lock()
+	defer st.Unlock()
+	return len(st.sessions)
+}
+
+// Snapshot of all active sessions.
+func (st *sessionStore) all() []*session {
+	st.Lock()
+	defer st.Unlock()
+	all := make([]*session, 0, len(st.sessions))
+	for _, s := range st.sessions {
+		all = append(all, s)
+	}
+	return all
+}
+
+// Remove every session that has not been used within ttl.
+// Returns the sessions that were removed.
+func (st *sessionStore) expire(ttl time.Duration) []*session {
+	st.Lock()
+	defer st.Unlock()
+	expired := []*session{}
+	cutoff := time.Now().Add(-ttl)
+	for id, s := range st.sessions {
+		if s.lastSeen.Before(cutoff) {
+			expired = append(expired, s)
+			delete(st.sessions, id)
+		}
+	}
+	return expired
+}
+
+// Periodically expire idle sessions until stop is closed.
+func (st *sessionStore) reap(ttl time.Duration, stop <-chan struct{}, onExpire func(*session)) {
+	interval := ttl / 2
+	if interval <= 0 {
+		return
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			for _, s := range st.expire(ttl) {
+				if onExpire != nil {
+					onExpire(s)
+				}
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+func newSessionID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
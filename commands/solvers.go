@@ -0,0 +1,46 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/golangchallenge/gc6/solvers"
+	"github.com/spf13/cobra"
+)
+
+// Defining the solvers command.
+// This will be called as 'laybrinth solvers'
+var solversCmd = &cobra.Command{
+	Use:   "solvers",
+	Short: "Maze solvers icarus can use",
+}
+
+// This will be called as 'laybrinth solvers list'
+var solversListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the available maze solvers",
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, r := range solvers.List() {
+			fmt.Printf("%-10s %s\n", r.Name, r.Description)
+		}
+	},
+}
+
+func init() {
+	solversCmd.AddCommand(solversListCmd)
+	RootCmd.AddCommand(solversCmd)
+}
@@ -0,0 +1,86 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/golangchallenge/gc6/generators"
+	"github.com/golangchallenge/gc6/mazelib"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Defining the stream command.
+// This will be called as 'laybrinth stream'
+var streamCmd = &cobra.Command{
+	Use:   "stream",
+	Short: "Write a laybrinth as text one row at a time",
+	Long: `Generate a laybrinth with Eller's algorithm and write it in the same
+  text format as the mazes printed by daedalus, one row at a time.
+  Only a single row is held in memory, so --height can be in the millions.
+
+  Without --out the maze is written to stdout. The output can be read back
+  with mazelib.ParseASCII.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		out, _ := cmd.Flags().GetString("out")
+		if err := runStream(out); err != nil {
+			fmt.Println(err)
+			os.Exit(-1)
+		}
+	},
+}
+
+func init() {
+	streamCmd.Flags().StringP("out", "o", "", "file to write the maze to")
+	RootCmd.AddCommand(streamCmd)
+}
+
+func runStream(out string) (err error) {
+	width, height := viper.GetInt("width"), viper.GetInt("height")
+	if width < 1 || height < 1 || width*height < 2 {
+		return fmt.Errorf("can't stream a %dx%d laybrinth, it needs room for a start and a treasure", width, height)
+	}
+
+	var w io.Writer = os.Stdout
+	if out != "" && out != "-" {
+		f, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+		}()
+		w = f
+	}
+	buf := bufio.NewWriter(w)
+
+	e := generators.NewEller(width, height, newRand(viper.GetInt64("seed")))
+	if err := mazelib.FprintTopBorder(buf, width); err != nil {
+		return err
+	}
+	for row := e.Next(); row != nil; row = e.Next() {
+		if err := mazelib.FprintRow(buf, row); err != nil {
+			return err
+		}
+	}
+	return buf.Flush()
+}
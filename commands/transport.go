@@ -0,0 +1,109 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golangchallenge/gc6/mazelib"
+	"github.com/spf13/viper"
+)
+
+// Transport is how Icarus reaches a daedalus.
+// Client talks to one over HTTP, while LocalTransport runs daedalus in-process.
+type Transport interface {
+	// Start a new maze, returning the survey of the starting room.
+	Awake() (mazelib.Survey, error)
+	// Move Icarus one step. Returns ErrVictory once the treasure is found
+	// and ErrOutOfSteps if Icarus gave up.
+	Move(direction string) (mazelib.Survey, error)
+	// Finish solving mazes.
+	Done() error
+}
+
+// LocalTransport generates and solves mazes directly in memory, skipping HTTP entirely.
+// It keeps score the same way a daedalus session does.
+type LocalTransport struct {
+	maze     *mazelib.Maze
+	seeds    *seedSequence
+	scores   []int
+	failures int
+}
+
+func NewLocalTransport() *LocalTransport {
+	return &LocalTransport{seeds: newSeedSequence(viper.GetInt64("seed"))}
+}
+
+// Generate a new maze and wake Icarus up in it.
+func (t *LocalTransport) Awake() (mazelib.Survey, error) {
+	m, err := createMaze(t.seeds.Next())
+	if err != nil {
+		return mazelib.Survey{}, err
+	}
+	t.maze = m
+	return t.maze.Discover(t.maze.Icarus())
+}
+
+// Move Icarus one step in the current maze.
+func (t *LocalTransport) Move(direction string) (mazelib.Survey, error) {
+	if t.maze == nil {
+		return mazelib.Survey{}, errors.New("Icarus is not awake")
+	}
+
+	m := t.maze
+	err := m.Move(direction)
+	if err == mazelib.ErrOutOfSteps {
+		fmt.Printf("Gave up after %d steps (seed %d) \n", m.StepsTaken, m.Seed)
+		t.failures++
+		t.maze = nil
+		return mazelib.Survey{}, err
+	}
+	if err != nil {
+		return mazelib.Survey{}, err
+	}
+
+	x, y := m.Icarus()
+	if endX, endY := m.End(); x == endX && y == endY {
+		fmt.Printf("Victory achieved in %d steps (seed %d) \n", m.StepsTaken, m.Seed)
+		t.scores = append(t.scores, m.StepsTaken)
+		return mazelib.Survey{}, mazelib.ErrVictory
+	}
+	return m.Discover(x, y)
+}
+
+// Print the results of every maze solved.
+func (t *LocalTransport) Done() error {
+	printResults(t.Result())
+	return nil
+}
+
+// The tally for every maze solved so far.
+func (t *LocalTransport) Result() SessionResult {
+	return SessionResult{ID: "local", Scores: append([]int(nil), t.scores...), Failures: t.failures}
+}
+
+// Pick the transport Icarus should use based on the --transport flag.
+// url is only used by the http transport.
+func newTransport(url string) (Transport, error) {
+	switch viper.GetString("transport") {
+	case "", "http":
+		return NewClient(url), nil
+	case "local":
+		return NewLocalTransport(), nil
+	}
+	return nil, fmt.Errorf("unknown transport %q", viper.GetString("transport"))
+}
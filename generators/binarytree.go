@@ -0,0 +1,89 @@
+package generators
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/golangchallenge/gc6/mazelib"
+)
+
+func init() {
+	options := map[string]string{"corner": "NE (default), NW, SE or SW. The corner every passage leads towards"}
+	for k, v := range placementOptions {
+		options[k] = v
+	}
+	Register(Registration{
+		Name:        "binary-tree",
+		Description: "Binary Tree (Diagonal bias, open edges)",
+		Options:     options,
+		New: func(opts Options) (Generator, error) {
+			corner := opts["corner"]
+			if _, _, err := cornerDirs(corner); err != nil {
+				return nil, err
+			}
+			return placed(carver{carve: func(m *mazelib.Maze, rng *rand.Rand) { binaryTree(m, corner, rng) }}, opts)
+		},
+	})
+}
+
+// The directions to dig for a corner bias: one vertical and one horizontal.
+// An empty corner means NE.
+func cornerDirs(corner string) (vertical, horizontal string, err error) {
+	switch corner {
+	case "", "NE":
+		return "up", "right", nil
+	case "NW":
+		return "up", "left", nil
+	case "SE":
+		return "down", "right", nil
+	case "SW":
+		return "down", "left", nil
+	}
+	return "", "", fmt.Errorf("unknown corner %q. Choose NE, NW, SE or SW", corner)
+}
+
+// Create a new maze with the binary tree algorithm.
+// Every room digs either vertically or horizontally towards the given
+// corner (NE, NW, SE or SW; empty means NE), picked at random. The two
+// edges meeting at that corner become long open corridors, and every path
+// to the corner runs diagonally without ever doubling back.
+// An unknown corner is treated as NE.
+// All random choices are made with rng.
+func BinaryTree(width, height int, corner string, rng *rand.Rand) *mazelib.Maze {
+	return carver{carve: func(m *mazelib.Maze, rng *rand.Rand) { binaryTree(m, corner, rng) }}.Generate(width, height, rng)
+}
+
+// Carve a binary tree maze into m, which starts with every wall up.
+func binaryTree(m *mazelib.Maze, corner string, rng *rand.Rand) {
+	v, h, err := cornerDirs(corner)
+	if err != nil {
+		v, h, _ = cornerDirs("")
+	}
+	for y := 0; y < m.Height(); y++ {
+		for x := 0; x < m.Width(); x++ {
+			if m.Solid(x, y) {
+				continue
+			}
+			c := mazelib.Coordinate{x, y}
+			possible := []string{}
+			for _, p := range neighbors(m, c) {
+				if p.dir == v || p.dir == h {
+					possible = append(possible, p.dir)
+				}
+			}
+			if len(possible) > 0 {
+				digInto(possible[rng.Intn(len(possible))], c, m)
+			}
+		}
+	}
+}
+
+// Is there an open room on the dir side of c?
+func canDig(m *mazelib.Maze, c mazelib.Coordinate, dir string) bool {
+	for _, p := range neighbors(m, c) {
+		if p.dir == dir {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package generators
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/golangchallenge/gc6/mazelib"
+)
+
+// Braid removes dead ends by knocking down walls, turning a perfect maze
+// into one with loops. fraction, from 0 to 1, is the share of the dead ends
+// that are removed, picked at random. Each is opened into a neighboring
+// dead end where there is one, so a single wall can remove two at once.
+// Walls are removed from both sides, and Animate is called after each.
+// All random choices are made with rng.
+func Braid(m *mazelib.Maze, fraction float64, rng *rand.Rand) {
+	if fraction <= 0 {
+		return
+	}
+	if fraction > 1 {
+		fraction = 1
+	}
+	ends := []mazelib.Coordinate{}
+	for y := 0; y < m.Height(); y++ {
+		for x := 0; x < m.Width(); x++ {
+			c := mazelib.Coordinate{x, y}
+			if !m.Solid(x, y) && len(openings(m, c)) == 1 {
+				ends = append(ends, c)
+			}
+		}
+	}
+	rng.Shuffle(len(ends), func(i, j int) { ends[i], ends[j] = ends[j], ends[i] })
+	ends = ends[:int(math.Round(fraction*float64(len(ends))))]
+
+	for _, c := range ends {
+		// An earlier wall may have opened this one up already
+		if len(openings(m, c)) != 1 {
+			continue
+		}
+		closed, deadEnds := []possibility{}, []possibility{}
+		for _, p := range neighbors(m, c) {
+			r, _ := m.GetRoom(c.X, c.Y)
+			if !blocked(r.Walls, p.dir) {
+				continue
+			}
+			closed = append(closed, p)
+			if len(openings(m, p.coord)) == 1 {
+				deadEnds = append(deadEnds, p)
+			}
+		}
+		if len(deadEnds) > 0 {
+			closed = deadEnds
+		}
+		if len(closed) > 0 {
+			digInto(closed[rng.Intn(len(closed))].dir, c, m)
+		}
+	}
+}
+
+// The neighbors a room has no wall between.
+func openings(m *mazelib.Maze, c mazelib.Coordinate) []possibility {
+	r, _ := m.GetRoom(c.X, c.Y)
+	open := []possibility{}
+	for _, p := range neighbors(m, c) {
+		if !blocked(r.Walls, p.dir) {
+			open = append(open, p)
+		}
+	}
+	return open
+}
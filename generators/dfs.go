@@ -9,11 +9,63 @@ import (
 
 var Animate func(m *mazelib.Maze) = nil
 
+func init() {
+	dfsBias := func(bias string) func(Options) (Generator, error) {
+		return func(Options) (Generator, error) {
+			return carver{carve: func(m *mazelib.Maze, rng *rand.Rand) { depthFirst(m, bias, rng) }}, nil
+		}
+	}
+	Register(Registration{
+		Name:        "dfs-o",
+		Description: "Depth First (Straight to treasure)",
+		New:         dfsBias("O"),
+	})
+	Register(Registration{
+		Name:        "dfs",
+		Description: "Depth First",
+		Options:     map[string]string{"bias": "H, V, X or O. See DepthFirst"},
+		New: func(opts Options) (Generator, error) {
+			return dfsBias(opts["bias"])(opts)
+		},
+	})
+	Register(Registration{
+		Name:        "dfs-h",
+		Description: "Depth First (Horizontal Bias)",
+		New:         dfsBias("H"),
+	})
+	Register(Registration{
+		Name:        "dfs-v",
+		Description: "Depth First (Vertical Bias)",
+		New:         dfsBias("V"),
+	})
+	Register(Registration{
+		Name:        "dfs-x",
+		Description: "Depth First (Anti-Treasure Bias)",
+		New:         dfsBias("X"),
+	})
+}
+
 type possibility struct {
 	dir   string
 	coord mazelib.Coordinate
 }
 
+// Every room next to c in the maze that isn't solid, with the direction to get there.
+func neighbors(m *mazelib.Maze, c mazelib.Coordinate) []possibility {
+	all := make([]possibility, 0, 4)
+	for _, p := range []possibility{
+		{"left", mazelib.Coordinate{c.X - 1, c.Y}},
+		{"right", mazelib.Coordinate{c.X + 1, c.Y}},
+		{"up", mazelib.Coordinate{c.X, c.Y - 1}},
+		{"down", mazelib.Coordinate{c.X, c.Y + 1}},
+	} {
+		if p.coord.X >= 0 && p.coord.Y >= 0 && p.coord.X < m.Width() && p.coord.Y < m.Height() && !m.Solid(p.coord.X, p.coord.Y) {
+			all = append(all, p)
+		}
+	}
+	return all
+}
+
 //Create a new Depth-First maze with the given bias.
 //Valid biases are:
 //"H": Prefer horizontal paths over vertical
@@ -21,8 +73,13 @@ type possibility struct {
 //"X": Choose cell furthest from goal. This will create a long winding path from start to finish.
 //"O": Chose path closest to goal. This will create a direct path to treasure and the rest will be a disconnected component. Goal being to bait icarus into taking the wrong path and having to backtrack.
 //"", or any other value:  Default, no bias. Always picka a random neighbor.
-func DepthFirst(width, height int, bias string) *mazelib.Maze {
-	m := mazelib.FullMaze(width, height)
+//All random choices are made with rng.
+func DepthFirst(width, height int, bias string, rng *rand.Rand) *mazelib.Maze {
+	return carver{carve: func(m *mazelib.Maze, rng *rand.Rand) { depthFirst(m, bias, rng) }}.Generate(width, height, rng)
+}
+
+// Carve a Depth-First maze into m, which starts with every wall up.
+func depthFirst(m *mazelib.Maze, bias string, rng *rand.Rand) {
 	x, y := m.End() //search treasure -> icarus so treasure is usually in a dead end.
 	startCoord := mazelib.Coordinate{x, y}
 	visited := map[mazelib.Coordinate]bool{}
@@ -32,27 +89,16 @@ func DepthFirst(width, height int, bias string) *mazelib.Maze {
 	for len(current) > 0 {
 		possible := []possibility{}
 		tip := current[len(current)-1]
-		leftCoord := mazelib.Coordinate{tip.X - 1, tip.Y}
-		if tip.X > 0 && !visited[leftCoord] {
-			possible = append(possible, possibility{"left", leftCoord})
-		}
-		rightCoord := mazelib.Coordinate{tip.X + 1, tip.Y}
-		if tip.X < width-1 && !visited[rightCoord] {
-			possible = append(possible, possibility{"right", rightCoord})
-		}
-		upCoord := mazelib.Coordinate{tip.X, tip.Y - 1}
-		if tip.Y > 0 && !visited[upCoord] {
-			possible = append(possible, possibility{"up", upCoord})
-		}
-		downCoord := mazelib.Coordinate{tip.X, tip.Y + 1}
-		if tip.Y < height-1 && !visited[downCoord] {
-			possible = append(possible, possibility{"down", downCoord})
+		for _, p := range neighbors(m, tip) {
+			if !visited[p.coord] {
+				possible = append(possible, p)
+			}
 		}
 		if len(possible) == 0 {
 			current = current[:len(current)-1]
 			continue
 		}
-		dir := randomDir(possible, tip.X, tip.Y, goalX, goalY, bias)
+		dir := randomDir(possible, tip.X, tip.Y, goalX, goalY, bias, rng)
 		newCoord := digInto(dir, tip, m)
 		visited[newCoord] = true
 		current = append(current, newCoord)
@@ -61,23 +107,13 @@ func DepthFirst(width, height int, bias string) *mazelib.Maze {
 	// so that everything not on the path will be connected, and likely fully explored before backtracking
 	// to the right route. Also makes choosing correct path less likely.
 	if bias == "O" {
-		if goalX > 0 {
-			digInto("left", mazelib.Coordinate{goalX, goalY}, m)
-		}
-		if goalX < width-1 {
-			digInto("right", mazelib.Coordinate{goalX, goalY}, m)
-		}
-		if goalY > 0 {
-			digInto("up", mazelib.Coordinate{goalX, goalY}, m)
-		}
-		if goalY < height-1 {
-			digInto("down", mazelib.Coordinate{goalX, goalY}, m)
+		for _, p := range neighbors(m, mazelib.Coordinate{goalX, goalY}) {
+			digInto(p.dir, mazelib.Coordinate{goalX, goalY}, m)
 		}
 	}
-	return m
 }
 
-func randomDir(possible []possibility, x, y, avoidX, avoidY int, bias string) string {
+func randomDir(possible []possibility, x, y, avoidX, avoidY int, bias string, rng *rand.Rand) string {
 	newPossible := possible
 	increaseWeight := func(p possibility) {
 		newPossible = append(newPossible, p)
@@ -118,10 +154,21 @@ func randomDir(possible []possibility, x, y, avoidX, avoidY int, bias string) st
 		}
 		return possible[maxAt].dir
 	}
-	return newPossible[rand.Intn(len(newPossible))].dir
+	return newPossible[rng.Intn(len(newPossible))].dir
 }
 
+// Knock down the wall on the dir side of a room, and the matching wall of
+// its neighbor, then call Animate. Returns the neighbor.
 func digInto(dir string, current mazelib.Coordinate, m *mazelib.Maze) mazelib.Coordinate {
+	c := removeWall(dir, current, m)
+	if Animate != nil {
+		Animate(m)
+	}
+	return c
+}
+
+// digInto without the Animate call, for changes that may be undone again.
+func removeWall(dir string, current mazelib.Coordinate, m *mazelib.Maze) mazelib.Coordinate {
 	var c mazelib.Coordinate
 	switch dir {
 	case "left":
@@ -149,8 +196,5 @@ func digInto(dir string, current mazelib.Coordinate, m *mazelib.Maze) mazelib.Co
 		roomA.RmWall(mazelib.S)
 		roomB.RmWall(mazelib.N)
 	}
-	if Animate != nil {
-		Animate(m)
-	}
 	return c
 }
@@ -0,0 +1,204 @@
+package generators
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+
+	"github.com/golangchallenge/gc6/mazelib"
+)
+
+func init() {
+	options := map[string]string{
+		"bias":        "H or V, to prefer walls running that way. Anything else is unbiased",
+		"min-chamber": "Never cut a chamber narrower than this many rooms (default 1). Larger values leave open areas",
+		"rooms":       "Chance from 0 to 1 of leaving a small chamber open as a room (default 0)",
+		"room-size":   "Largest chamber, in rooms across, that may be left open by rooms (default 4)",
+	}
+	for k, v := range placementOptions {
+		options[k] = v
+	}
+	Register(Registration{
+		Name:        "division",
+		Description: "Recursive Division (Long straight walls)",
+		Options:     options,
+		New: func(opts Options) (Generator, error) {
+			d := Division{Bias: opts["bias"], MinChamber: 1, RoomSize: 4}
+			var err error
+			if v := opts["min-chamber"]; v != "" {
+				if d.MinChamber, err = strconv.Atoi(v); err != nil || d.MinChamber < 1 {
+					return nil, fmt.Errorf("min-chamber must be a whole number of at least 1, not %q", v)
+				}
+			}
+			if v := opts["rooms"]; v != "" {
+				if d.Rooms, err = strconv.ParseFloat(v, 64); err != nil || d.Rooms < 0 || d.Rooms > 1 {
+					return nil, fmt.Errorf("rooms must be a number from 0 to 1, not %q", v)
+				}
+			}
+			if v := opts["room-size"]; v != "" {
+				if d.RoomSize, err = strconv.Atoi(v); err != nil || d.RoomSize < 1 {
+					return nil, fmt.Errorf("room-size must be a whole number of at least 1, not %q", v)
+				}
+			}
+			return placed(d, opts)
+		},
+	})
+}
+
+// Division builds mazes by recursive division, the additive opposite of
+// DepthFirst: it starts from mazelib.EmptyMaze and keeps splitting chambers
+// in two with a wall that has a single gap in it.
+// With the zero values for Rooms and a MinChamber of 1 the result is a perfect maze.
+type Division struct {
+	// "H" prefers horizontal walls, "V" vertical ones, four to one like the
+	// DepthFirst biases. Otherwise long, narrow chambers are cut across and
+	// square ones either way.
+	Bias string
+	// Chambers narrower than this, in the direction they would be cut, are never cut.
+	// A chamber too small to cut either way is left as an open area.
+	MinChamber int
+	// Chance of leaving a chamber open once it is no more than RoomSize rooms
+	// in either direction, even though it could still be cut.
+	Rooms    float64
+	RoomSize int
+}
+
+// A rectangle of rooms with no walls inside it yet.
+type chamber struct {
+	x, y, w, h int
+}
+
+// Generate implements Generator. Animate is called after each wall is added.
+// All random choices are made with rng.
+func (d Division) Generate(width, height int, rng *rand.Rand) *mazelib.Maze {
+	return carver{additive: true, carve: d.carve}.Generate(width, height, rng)
+}
+
+// GenerateMasked implements MaskedGenerator.
+func (d Division) GenerateMasked(k *mazelib.Mask, rng *rand.Rand) *mazelib.Maze {
+	return carver{additive: true, carve: d.carve}.GenerateMasked(k, rng)
+}
+
+func (d Division) carve(m *mazelib.Maze, rng *rand.Rand) {
+	min := d.MinChamber
+	if min < 1 {
+		min = 1
+	}
+	d.divide(m, chamber{0, 0, m.Width(), m.Height()}, min, rng)
+}
+
+func (d Division) divide(m *mazelib.Maze, c chamber, min int, rng *rand.Rand) {
+	canCutAcross := c.h >= 2*min // with a horizontal wall
+	canCutDown := c.w >= 2*min   // with a vertical wall
+	if !canCutAcross && !canCutDown {
+		return
+	}
+	if d.Rooms > 0 && c.w <= d.RoomSize && c.h <= d.RoomSize && rng.Float64() < d.Rooms {
+		return
+	}
+
+	horizontal := canCutAcross
+	if canCutAcross && canCutDown {
+		horizontal = d.horizontal(c, rng)
+	}
+
+	if horizontal {
+		// The wall runs along the bottom of row y, leaving at least min rows on each side
+		y := c.y + min - 1 + rng.Intn(c.h-2*min+1)
+		if gap := pickGap(c.w, func(i int) bool { return !m.Solid(c.x+i, y) && !m.Solid(c.x+i, y+1) }, rng); gap >= 0 {
+			for x := c.x; x < c.x+c.w; x++ {
+				if x != c.x+gap {
+					addWall("down", mazelib.Coordinate{x, y}, m)
+				}
+			}
+			animate(m)
+		}
+		d.divide(m, chamber{c.x, c.y, c.w, y - c.y + 1}, min, rng)
+		d.divide(m, chamber{c.x, y + 1, c.w, c.y + c.h - y - 1}, min, rng)
+		return
+	}
+
+	// The wall runs along the right of column x
+	x := c.x + min - 1 + rng.Intn(c.w-2*min+1)
+	if gap := pickGap(c.h, func(i int) bool { return !m.Solid(x, c.y+i) && !m.Solid(x+1, c.y+i) }, rng); gap >= 0 {
+		for y := c.y; y < c.y+c.h; y++ {
+			if y != c.y+gap {
+				addWall("right", mazelib.Coordinate{x, y}, m)
+			}
+		}
+		animate(m)
+	}
+	d.divide(m, chamber{c.x, c.y, x - c.x + 1, c.h}, min, rng)
+	d.divide(m, chamber{x + 1, c.y, c.x + c.w - x - 1, c.h}, min, rng)
+}
+
+// Where along a wall of length n to leave the gap. Only places where ok
+// says both sides are open will do, so masked mazes don't get a gap into
+// solid rock. Returns -1 if there is nowhere suitable. Then there is solid
+// rock along one side or the other of the whole line, so it is walled
+// already and no wall is built. The halves either side of it are left
+// for connect to join, which GenerateMasked runs afterwards.
+func pickGap(n int, ok func(i int) bool, rng *rand.Rand) int {
+	gap := rng.Intn(n)
+	if ok(gap) {
+		return gap
+	}
+	possible := []int{}
+	for i := 0; i < n; i++ {
+		if ok(i) {
+			possible = append(possible, i)
+		}
+	}
+	if len(possible) == 0 {
+		return -1
+	}
+	return possible[rng.Intn(len(possible))]
+}
+
+// Should a chamber that can be cut either way get a horizontal wall?
+func (d Division) horizontal(c chamber, rng *rand.Rand) bool {
+	switch d.Bias {
+	case "H":
+		return rng.Intn(5) != 0
+	case "V":
+		return rng.Intn(5) == 0
+	}
+	switch {
+	case c.w < c.h:
+		return true
+	case c.w > c.h:
+		return false
+	}
+	return rng.Intn(2) == 0
+}
+
+// Put up a wall on the dir side of a room, and the matching wall of its neighbor.
+// The additive counterpart of digInto, without the Animate call, since
+// walls are animated a whole line at a time.
+func addWall(dir string, current mazelib.Coordinate, m *mazelib.Maze) {
+	roomA, _ := m.GetRoom(current.X, current.Y)
+	switch dir {
+	case "left":
+		roomB, _ := m.GetRoom(current.X-1, current.Y)
+		roomA.AddWall(mazelib.W)
+		roomB.AddWall(mazelib.E)
+	case "right":
+		roomB, _ := m.GetRoom(current.X+1, current.Y)
+		roomA.AddWall(mazelib.E)
+		roomB.AddWall(mazelib.W)
+	case "up":
+		roomB, _ := m.GetRoom(current.X, current.Y-1)
+		roomA.AddWall(mazelib.N)
+		roomB.AddWall(mazelib.S)
+	case "down":
+		roomB, _ := m.GetRoom(current.X, current.Y+1)
+		roomA.AddWall(mazelib.S)
+		roomB.AddWall(mazelib.N)
+	}
+}
+
+func animate(m *mazelib.Maze) {
+	if Animate != nil {
+		Animate(m)
+	}
+}
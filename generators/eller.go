@@ -0,0 +1,193 @@
+package generators
+
+import (
+	"math/rand"
+
+	"github.com/golangchallenge/gc6/mazelib"
+)
+
+func init() {
+	Register(Registration{
+		Name:        "eller",
+		Description: "Eller's (Row by row)",
+		Options:     placementOptions,
+		New: func(opts Options) (Generator, error) {
+			return placed(carver{carve: eller}, opts)
+		},
+	})
+}
+
+// EllerStream generates a perfect maze one row at a time with Eller's algorithm.
+// Only the current row is kept in memory, so mazes can be far taller than
+// would fit as a mazelib.Maze. Rows can be written out as they come with
+// mazelib.FprintRow.
+//
+// The start is in the top row and the treasure in the bottom row.
+type EllerStream struct {
+	width, height int
+	rng           *rand.Rand
+	y             int
+	// Which set each room of the next row belongs to. Rooms in the same set
+	// are already connected by the rows above. 0 means not yet connected.
+	sets    []int
+	nextSet int
+	// Which rooms of the next row have a passage up into the row above.
+	open []bool
+	// Scratch space for the rooms of one set, reused for every set and row.
+	members           []int
+	startX, treasureX int
+	// Solid rooms, when building a masked maze with Eller. Sets that end up
+	// walled in by solid rooms are left for connect to join up.
+	mask *mazelib.Mask
+}
+
+// NewEller starts streaming a width x height maze.
+// All random choices are made with rng.
+func NewEller(width, height int, rng *rand.Rand) *EllerStream {
+	e := &EllerStream{
+		width:  width,
+		height: height,
+		rng:    rng,
+		sets:   make([]int, width),
+		open:   make([]bool, width),
+	}
+	e.startX = rng.Intn(width)
+	for {
+		e.treasureX = rng.Intn(width)
+		if height > 1 || e.treasureX != e.startX {
+			break
+		}
+	}
+	return e
+}
+
+// Start returns where Icarus wakes up.
+func (e *EllerStream) Start() mazelib.Coordinate {
+	return mazelib.Coordinate{e.startX, 0}
+}
+
+// Treasure returns where the treasure is.
+func (e *EllerStream) Treasure() mazelib.Coordinate {
+	return mazelib.Coordinate{e.treasureX, e.height - 1}
+}
+
+// Next returns the next row of rooms, top to bottom, or nil once every row has been returned.
+// The returned slice is new on every call, so it may be kept.
+func (e *EllerStream) Next() []mazelib.Room {
+	if e.y >= e.height {
+		return nil
+	}
+	last := e.y == e.height-1
+	solid := func(x, y int) bool { return e.mask != nil && e.mask.Solid(x, y) }
+	row := make([]mazelib.Room, e.width)
+	for x := range row {
+		if solid(x, e.y) {
+			e.sets[x] = -1
+		} else if e.sets[x] == 0 {
+			e.nextSet++
+			e.sets[x] = e.nextSet
+		}
+		row[x].Walls = mazelib.Survey{Top: !e.open[x], Right: true, Bottom: true, Left: true}
+	}
+
+	// Join neighbors that aren't connected yet. Randomly, except on the
+	// last row, where everything must end up connected.
+	for x := 0; x < e.width-1; x++ {
+		if e.sets[x] == e.sets[x+1] || e.sets[x] < 0 || e.sets[x+1] < 0 || (!last && e.rng.Intn(2) == 0) {
+			continue
+		}
+		row[x].Walls.Right = false
+		row[x+1].Walls.Left = false
+		from, to := e.sets[x+1], e.sets[x]
+		for i := range e.sets {
+			if e.sets[i] == from {
+				e.sets[i] = to
+			}
+		}
+	}
+
+	// Every set needs at least one passage down, or it would be cut off.
+	// Sets are visited in the order they first appear in the row.
+	for x := range e.open {
+		e.open[x] = false
+	}
+	if !last {
+		for x, set := range e.sets {
+			if set < 0 || e.seenBefore(x) {
+				continue
+			}
+			members := e.members[:0]
+			for i := x; i < e.width; i++ {
+				if e.sets[i] == set && !solid(i, e.y+1) {
+					members = append(members, i)
+				}
+			}
+			e.members = members
+			if len(members) == 0 {
+				continue
+			}
+			must := members[e.rng.Intn(len(members))]
+			for _, i := range members {
+				if i == must || e.rng.Intn(2) == 0 {
+					e.open[i] = true
+					row[i].Walls.Bottom = false
+				}
+			}
+		}
+	}
+	// Rooms without a passage up start the next row in a set of their own
+	for x := range e.sets {
+		if !e.open[x] {
+			e.sets[x] = 0
+		}
+	}
+
+	if e.y == 0 {
+		row[e.startX].Start = true
+	}
+	if last {
+		row[e.treasureX].Treasure = true
+	}
+	e.y++
+	return row
+}
+
+// Does the room at x belong to the same set as a room to its left?
+func (e *EllerStream) seenBefore(x int) bool {
+	for i := 0; i < x; i++ {
+		if e.sets[i] == e.sets[x] {
+			return true
+		}
+	}
+	return false
+}
+
+// Create a new maze with Eller's algorithm, holding the whole maze in memory.
+// Use NewEller to stream mazes too big for that.
+// Animate is called after each row is added.
+// All random choices are made with rng.
+func Eller(width, height int, rng *rand.Rand) *mazelib.Maze {
+	return carver{carve: eller}.Generate(width, height, rng)
+}
+
+// Copy an Eller's maze into m row by row.
+// Unless m is masked, the start and treasure are moved to where the stream put them.
+func eller(m *mazelib.Maze, rng *rand.Rand) {
+	e := NewEller(m.Width(), m.Height(), rng)
+	e.mask = m.Mask()
+	for y := 0; y < m.Height(); y++ {
+		for x, r := range e.Next() {
+			if m.Solid(x, y) {
+				continue
+			}
+			room, _ := m.GetRoom(x, y)
+			room.Walls = r.Walls
+		}
+		if Animate != nil {
+			Animate(m)
+		}
+	}
+	if e.mask == nil {
+		m.MoveStartAndEnd(e.Start(), e.Treasure())
+	}
+}
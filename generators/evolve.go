@@ -0,0 +1,210 @@
+package generators
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/golangchallenge/gc6/mazelib"
+	"github.com/golangchallenge/gc6/solvers"
+)
+
+// Evolver searches for mazes that a particular solver finds hard.
+// Where the DepthFirst "O" bias guesses at what will make Icarus backtrack,
+// Evolver measures it: it hill climbs from a starting maze, moving one passage
+// at a time and keeping the change whenever the solver needs at least as many
+// steps on average as before.
+type Evolver struct {
+	// Creates the solver to score mazes against. Every run gets a fresh one.
+	Solver func(rng *rand.Rand) solvers.MazeSolver
+	// How many times each maze is solved to score it. Each run seeds its
+	// solver differently, but the same seeds are used for every maze, so
+	// scores can be compared without the luck of the solver getting in the way.
+	Runs int
+	// Stop after this long, or after this many rounds, whichever comes first.
+	// Zero means no limit, but at least one of them must be set.
+	// Only a round limit makes the result the same for the same rng.
+	Budget time.Duration
+	Rounds int
+	// If not nil, called about once a second while the search runs, whether or
+	// not the score has improved, and once more when it is over.
+	Progress func(EvolveProgress)
+}
+
+// EvolveProgress reports how far an Evolver has got.
+type EvolveProgress struct {
+	Round    int
+	Elapsed  time.Duration
+	Initial  float64 // mean steps for the maze the search started from
+	Best     float64 // mean steps for the maze as it stands
+	Accepted int     // rounds whose change was kept
+}
+
+// A wall between two open rooms. dir is "right" or "down" from coord.
+type wall possibility
+
+// Evolve changes the walls of m to make the solver take as many steps as it can.
+// m must be valid, and stays valid, connected and with the same number of
+// passages throughout: the start and treasure never move, and every round
+// puts up one wall and knocks down another.
+// Runs that run out of steps score m.MaxSteps, so set it if the solver might never find the treasure.
+// Mutations are chosen and runs seeded with rng.
+func (e Evolver) Evolve(m *mazelib.Maze, rng *rand.Rand) (EvolveProgress, error) {
+	p := EvolveProgress{}
+	switch {
+	case e.Solver == nil:
+		return p, errors.New("evolve needs a solver")
+	case e.Runs < 1:
+		return p, errors.New("evolve needs at least one run per maze")
+	case e.Budget <= 0 && e.Rounds <= 0:
+		return p, errors.New("evolve needs a time budget or a number of rounds")
+	case len(m.Validate()) > 0:
+		return p, errors.New("can't evolve an invalid maze")
+	}
+
+	seeds := make([]int64, e.Runs)
+	for i := range seeds {
+		seeds[i] = rng.Int63()
+	}
+	began := time.Now()
+	best, err := e.score(m, seeds)
+	if err != nil {
+		return p, err
+	}
+	p.Initial, p.Best = best, best
+	reported := began
+
+	for p.Round = 0; e.Rounds <= 0 || p.Round < e.Rounds; p.Round++ {
+		if e.Budget > 0 && time.Since(began) >= e.Budget {
+			break
+		}
+		if e.Progress != nil && time.Since(reported) >= time.Second {
+			p.Elapsed = time.Since(began)
+			e.Progress(p)
+			reported = time.Now()
+		}
+		closed, opened, ok := mutate(m, rng)
+		if !ok {
+			continue
+		}
+		s, err := e.score(m, seeds)
+		if err != nil {
+			return p, err
+		}
+		if s < best {
+			removeWall(closed.dir, closed.coord, m)
+			addWall(opened.dir, opened.coord, m)
+			continue
+		}
+		p.Accepted++
+		best, p.Best = s, s
+	}
+	p.Elapsed = time.Since(began)
+	if e.Progress != nil {
+		e.Progress(p)
+	}
+	return p, nil
+}
+
+// The mean steps taken by the solver over one run per seed.
+// Icarus is put back at the start before and after every run.
+func (e Evolver) score(m *mazelib.Maze, seeds []int64) (float64, error) {
+	sx, sy := m.Start()
+	defer func() {
+		m.SetStartPoint(sx, sy)
+		m.StepsTaken = 0
+	}()
+	total := 0
+	for _, seed := range seeds {
+		m.SetStartPoint(sx, sy)
+		m.StepsTaken = 0
+		steps, err := solvers.Solve(m, e.Solver(rand.New(rand.NewSource(seed))), nil)
+		if err != nil && err != mazelib.ErrOutOfSteps {
+			return 0, err
+		}
+		total += steps
+	}
+	return float64(total) / float64(len(seeds)), nil
+}
+
+// Put up the wall of a random passage and knock down another wall so every
+// open room can still be reached. If the new wall cut the maze in two, the
+// wall knocked down joins the halves back up, otherwise it can be anywhere.
+// Returns false, leaving m as it was, if there was nothing to move.
+func mutate(m *mazelib.Maze, rng *rand.Rand) (closed, opened wall, ok bool) {
+	passages, walls := interior(m)
+	if len(passages) == 0 || len(walls) == 0 {
+		return closed, opened, false
+	}
+	closed = passages[rng.Intn(len(passages))]
+	addWall(closed.dir, closed.coord, m)
+
+	side := reach(m, closed.coord)
+	candidates := []wall{}
+	if side[across(closed)] {
+		candidates = walls
+	} else {
+		for _, w := range walls {
+			if side[w.coord] != side[across(w)] {
+				candidates = append(candidates, w)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		removeWall(closed.dir, closed.coord, m)
+		return closed, opened, false
+	}
+	opened = candidates[rng.Intn(len(candidates))]
+	removeWall(opened.dir, opened.coord, m)
+	return closed, opened, true
+}
+
+// Every passage and every wall between two open rooms, each counted once.
+func interior(m *mazelib.Maze) (passages, walls []wall) {
+	for y := 0; y < m.Height(); y++ {
+		for x := 0; x < m.Width(); x++ {
+			if m.Solid(x, y) {
+				continue
+			}
+			c := mazelib.Coordinate{x, y}
+			r, _ := m.GetRoom(x, y)
+			for _, p := range neighbors(m, c) {
+				if p.dir != "right" && p.dir != "down" {
+					continue
+				}
+				if blocked(r.Walls, p.dir) {
+					walls = append(walls, wall{p.dir, c})
+				} else {
+					passages = append(passages, wall{p.dir, c})
+				}
+			}
+		}
+	}
+	return passages, walls
+}
+
+// The room on the other side of w.
+func across(w wall) mazelib.Coordinate {
+	if w.dir == "right" {
+		return mazelib.Coordinate{w.coord.X + 1, w.coord.Y}
+	}
+	return mazelib.Coordinate{w.coord.X, w.coord.Y + 1}
+}
+
+// Every room that can be walked to from c.
+func reach(m *mazelib.Maze, c mazelib.Coordinate) map[mazelib.Coordinate]bool {
+	seen := map[mazelib.Coordinate]bool{c: true}
+	queue := []mazelib.Coordinate{c}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		r, _ := m.GetRoom(n.X, n.Y)
+		for _, p := range neighbors(m, n) {
+			if !seen[p.coord] && !blocked(r.Walls, p.dir) {
+				seen[p.coord] = true
+				queue = append(queue, p.coord)
+			}
+		}
+	}
+	return seen
+}
@@ -0,0 +1,147 @@
+package generators
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/golangchallenge/gc6/mazelib"
+)
+
+// The policy growing-tree uses when none is given.
+const defaultPolicy = "newest:75,random:25"
+
+func init() {
+	options := map[string]string{
+		"policy": "Which room to grow from next. newest, oldest, random, middle, or a mix like newest:75,random:25 (the default). See ParseSelector",
+	}
+	for k, v := range placementOptions {
+		options[k] = v
+	}
+	Register(Registration{
+		Name:        "growing-tree",
+		Description: "Growing Tree (Between Depth First and Prim's)",
+		Options:     options,
+		New: func(opts Options) (Generator, error) {
+			policy := opts["policy"]
+			if policy == "" {
+				policy = defaultPolicy
+			}
+			pick, err := ParseSelector(policy)
+			if err != nil {
+				return nil, err
+			}
+			return placed(carver{carve: func(m *mazelib.Maze, rng *rand.Rand) { growingTree(m, pick, rng) }}, opts)
+		},
+	})
+}
+
+// Selector chooses which active room growing-tree grows from next.
+// Active rooms are numbered from 0, the oldest, to n-1, the newest.
+// n is always at least 1.
+type Selector func(n int, rng *rand.Rand) int
+
+// The basic selectors. Newest gives DepthFirst's long corridors, Random
+// gives Prim's short dead ends, and Oldest gives long straight passages
+// radiating from the first room.
+var (
+	Newest Selector = func(n int, rng *rand.Rand) int { return n - 1 }
+	Oldest Selector = func(n int, rng *rand.Rand) int { return 0 }
+	Random Selector = func(n int, rng *rand.Rand) int { return rng.Intn(n) }
+	Middle Selector = func(n int, rng *rand.Rand) int { return n / 2 }
+)
+
+var selectors = map[string]Selector{
+	"newest": Newest,
+	"oldest": Oldest,
+	"random": Random,
+	"middle": Middle,
+}
+
+// Mix picks one of the selectors at random each time, in proportion to its weight.
+// The weights don't need to add up to anything in particular.
+func Mix(weights []float64, choices []Selector) Selector {
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+	return func(n int, rng *rand.Rand) int {
+		r := rng.Float64() * total
+		for i, w := range weights {
+			if r < w {
+				return choices[i](n, rng)
+			}
+			r -= w
+		}
+		return choices[len(choices)-1](n, rng)
+	}
+}
+
+// ParseSelector reads a policy: either the name of a basic selector
+// (newest, oldest, random or middle) or a comma separated mix of them with
+// weights, like "newest:75,random:25".
+func ParseSelector(policy string) (Selector, error) {
+	if s, ok := selectors[policy]; ok {
+		return s, nil
+	}
+	weights, choices := []float64{}, []Selector{}
+	for _, part := range strings.Split(policy, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), ":", 2)
+		s, ok := selectors[kv[0]]
+		if !ok {
+			return nil, fmt.Errorf("unknown selector %q in policy %q. Choose newest, oldest, random or middle", kv[0], policy)
+		}
+		w := 1.0
+		if len(kv) == 2 {
+			var err error
+			if w, err = strconv.ParseFloat(kv[1], 64); err != nil || w < 0 {
+				return nil, fmt.Errorf("bad weight %q for %s in policy %q", kv[1], kv[0], policy)
+			}
+		}
+		weights = append(weights, w)
+		choices = append(choices, s)
+	}
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("policy %q has no weight", policy)
+	}
+	return Mix(weights, choices), nil
+}
+
+// Create a new maze with the growing tree algorithm.
+// Starting from a random room, keep a list of active rooms. Each step pick
+// asks which one to grow from: dig into one of its unvisited neighbors at
+// random, which becomes active too, or retire it if there are none.
+// All random choices are made with rng.
+func GrowingTree(width, height int, pick Selector, rng *rand.Rand) *mazelib.Maze {
+	return carver{carve: func(m *mazelib.Maze, rng *rand.Rand) { growingTree(m, pick, rng) }}.Generate(width, height, rng)
+}
+
+// Carve a growing tree maze into m, which starts with every wall up.
+func growingTree(m *mazelib.Maze, pick Selector, rng *rand.Rand) {
+	start := randomRoom(m, rng)
+	visited := map[mazelib.Coordinate]bool{start: true}
+	active := []mazelib.Coordinate{start}
+	for len(active) > 0 {
+		i := pick(len(active), rng)
+		c := active[i]
+		possible := []possibility{}
+		for _, p := range neighbors(m, c) {
+			if !visited[p.coord] {
+				possible = append(possible, p)
+			}
+		}
+		if len(possible) == 0 {
+			active = append(active[:i], active[i+1:]...)
+			continue
+		}
+		p := possible[rng.Intn(len(possible))]
+		digInto(p.dir, c, m)
+		visited[p.coord] = true
+		active = append(active, p.coord)
+	}
+}
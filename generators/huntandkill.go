@@ -0,0 +1,80 @@
+package generators
+
+import (
+	"math/rand"
+
+	"github.com/golangchallenge/gc6/mazelib"
+)
+
+func init() {
+	Register(Registration{
+		Name:        "hunt-and-kill",
+		Description: "Hunt and Kill (Long winding corridors)",
+		Options:     placementOptions,
+		New: func(opts Options) (Generator, error) {
+			return placed(carver{carve: huntAndKill}, opts)
+		},
+	})
+}
+
+// Create a new maze with the hunt and kill algorithm.
+// Like DepthFirst it walks randomly into unvisited rooms, but instead of
+// backtracking when stuck it hunts, scanning the rows from the top for an
+// unvisited room next to a visited one, joins the two and walks on from there.
+// All random choices are made with rng.
+func HuntAndKill(width, height int, rng *rand.Rand) *mazelib.Maze {
+	return carver{carve: huntAndKill}.Generate(width, height, rng)
+}
+
+// Carve a hunt and kill maze into m, which starts with every wall up.
+func huntAndKill(m *mazelib.Maze, rng *rand.Rand) {
+	width, height := m.Width(), m.Height()
+	c := randomRoom(m, rng)
+	visited := map[mazelib.Coordinate]bool{c: true}
+	// Rows above this are completely visited, so the hunt can skip them
+	huntFrom := 0
+	for {
+		possible := []possibility{}
+		for _, p := range neighbors(m, c) {
+			if !visited[p.coord] {
+				possible = append(possible, p)
+			}
+		}
+		if len(possible) > 0 {
+			p := possible[rng.Intn(len(possible))]
+			digInto(p.dir, c, m)
+			visited[p.coord] = true
+			c = p.coord
+			continue
+		}
+
+		found := false
+		for y := huntFrom; y < height && !found; y++ {
+			full := true
+			for x := 0; x < width && !found; x++ {
+				h := mazelib.Coordinate{x, y}
+				if visited[h] || m.Solid(x, y) {
+					continue
+				}
+				full = false
+				possible = possible[:0]
+				for _, p := range neighbors(m, h) {
+					if visited[p.coord] {
+						possible = append(possible, p)
+					}
+				}
+				if len(possible) > 0 {
+					digInto(possible[rng.Intn(len(possible))].dir, h, m)
+					visited[h] = true
+					c, found = h, true
+				}
+			}
+			if full && y == huntFrom {
+				huntFrom++
+			}
+		}
+		if !found {
+			return
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package generators
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/golangchallenge/gc6/mazelib"
+)
+
+func init() {
+	options := map[string]string{"bias": "H or V. Anything else is unbiased. See BiasWeight"}
+	for k, v := range placementOptions {
+		options[k] = v
+	}
+	Register(Registration{
+		Name:        "kruskal",
+		Description: "Kruskal's (Uniform looking)",
+		Options:     options,
+		New: func(opts Options) (Generator, error) {
+			weight := BiasWeight(opts["bias"])
+			return placed(carver{carve: func(m *mazelib.Maze, rng *rand.Rand) { kruskal(m, weight, rng) }}, opts)
+		},
+	})
+}
+
+// EdgeWeight gives the relative chance of knocking down the wall on the
+// dir side of room c. dir is "right" or "down", so each wall is only asked about once.
+// A wall with twice the weight of another tends to come down earlier.
+// Weights must be positive.
+type EdgeWeight func(c mazelib.Coordinate, dir string) float64
+
+// BiasWeight makes walls in one direction four times as likely to be
+// knocked down, matching the H and V biases of DepthFirst.
+// "H" prefers horizontal passages, "V" vertical ones.
+// Any other bias returns nil, for no bias, like DepthFirst.
+func BiasWeight(bias string) EdgeWeight {
+	var prefer string
+	switch bias {
+	case "H":
+		prefer = "right"
+	case "V":
+		prefer = "down"
+	default:
+		return nil
+	}
+	return func(c mazelib.Coordinate, dir string) float64 {
+		if dir == prefer {
+			return 4
+		}
+		return 1
+	}
+}
+
+// A wall between two rooms, and its place in the order walls are considered.
+type edge struct {
+	from mazelib.Coordinate
+	dir  string
+	key  float64
+}
+
+// Create a new maze with randomized Kruskal's algorithm.
+// Every wall is considered once in a random order, and knocked down when
+// the rooms on either side aren't already connected. This gives a perfect
+// maze without the long corridors of DepthFirst or the bushiness of Prim.
+// With a weight function walls are ordered by weighted sampling, so higher
+// weight walls tend to be considered first. A nil weight treats every wall the same.
+// Animate is called after every wall knocked down.
+// All random choices are made with rng.
+func Kruskal(width, height int, weight EdgeWeight, rng *rand.Rand) *mazelib.Maze {
+	return carver{carve: func(m *mazelib.Maze, rng *rand.Rand) { kruskal(m, weight, rng) }}.Generate(width, height, rng)
+}
+
+// Carve a Kruskal's maze into m, which starts with every wall up.
+func kruskal(m *mazelib.Maze, weight EdgeWeight, rng *rand.Rand) {
+	width, height := m.Width(), m.Height()
+	edges := []edge{}
+	open := 0
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := mazelib.Coordinate{x, y}
+			if m.Solid(x, y) {
+				continue
+			}
+			open++
+			for _, p := range neighbors(m, c) {
+				if p.dir == "right" || p.dir == "down" {
+					edges = append(edges, edge{from: c, dir: p.dir})
+				}
+			}
+		}
+	}
+	if weight == nil {
+		rng.Shuffle(len(edges), func(i, j int) { edges[i], edges[j] = edges[j], edges[i] })
+	} else {
+		// Weighted random order: sorting by u^(1/w) for uniform u puts each
+		// remaining wall next with probability proportional to its weight.
+		for i := range edges {
+			edges[i].key = math.Pow(rng.Float64(), 1/weight(edges[i].from, edges[i].dir))
+		}
+		sort.SliceStable(edges, func(i, j int) bool { return edges[i].key > edges[j].key })
+	}
+
+	sets := NewUnionFind(width * height)
+	index := func(c mazelib.Coordinate) int { return c.Y*width + c.X }
+	joined := 0
+	for _, e := range edges {
+		to := e.from
+		if e.dir == "right" {
+			to.X++
+		} else {
+			to.Y++
+		}
+		if sets.Union(index(e.from), index(to)) {
+			digInto(e.dir, e.from, m)
+			// Solid rooms stay in sets of their own
+			if joined++; joined == open-1 {
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package generators
+
+import (
+	"errors"
+	"math/rand"
+
+	"github.com/golangchallenge/gc6/mazelib"
+)
+
+// MaskedGenerator is a Generator that can also build mazes in the shape of a mask.
+// Every generator registered in this package is one.
+type MaskedGenerator interface {
+	Generator
+	// GenerateMasked builds a maze the size of the mask, leaving solid rooms alone.
+	// The mask's open rooms must all be connected. See mazelib.Mask.Connected.
+	GenerateMasked(k *mazelib.Mask, rng *rand.Rand) *mazelib.Maze
+}
+
+// Masked wraps g so every maze it makes has the shape of k,
+// whatever width and height it is asked for.
+func Masked(g Generator, k *mazelib.Mask) (Generator, error) {
+	mg, ok := g.(MaskedGenerator)
+	if !ok {
+		return nil, errors.New("generator can't make masked mazes")
+	}
+	if k.Open() < 2 {
+		return nil, errors.New("mask needs at least two open rooms, for the start and treasure")
+	}
+	if !k.Connected() {
+		return nil, errors.New("mask's open rooms must all be connected")
+	}
+	return GeneratorFunc(func(_, _ int, rng *rand.Rand) *mazelib.Maze {
+		return mg.GenerateMasked(k, rng)
+	}), nil
+}
+
+// carver is a MaskedGenerator for algorithms that work on a maze that has
+// already been set up, either with every wall or, if additive, none.
+// carve must never dig into solid rooms.
+type carver struct {
+	additive bool
+	carve    func(m *mazelib.Maze, rng *rand.Rand)
+}
+
+func (c carver) Generate(width, height int, rng *rand.Rand) *mazelib.Maze {
+	var m *mazelib.Maze
+	if c.additive {
+		m = mazelib.EmptyMaze(width, height, rng)
+	} else {
+		m = mazelib.FullMaze(width, height, rng)
+	}
+	if c.carve != nil {
+		c.carve(m, rng)
+	}
+	return m
+}
+
+// GenerateMasked implements MaskedGenerator. Algorithms that can be cut
+// off by solid rooms are patched up afterwards with connect.
+func (c carver) GenerateMasked(k *mazelib.Mask, rng *rand.Rand) *mazelib.Maze {
+	var m *mazelib.Maze
+	if c.additive {
+		m = mazelib.EmptyMaskedMaze(k, rng)
+	} else {
+		m = mazelib.FullMaskedMaze(k, rng)
+	}
+	if c.carve != nil {
+		c.carve(m, rng)
+	}
+	connect(m, rng)
+	return m
+}
+
+// Knock down walls between parts of the maze that can't reach each other,
+// in random order, until every open room is connected.
+// Does nothing to a maze that is already connected.
+func connect(m *mazelib.Maze, rng *rand.Rand) {
+	width, height := m.Width(), m.Height()
+	index := func(c mazelib.Coordinate) int { return c.Y*width + c.X }
+	sets := NewUnionFind(width * height)
+	walls := []possibility{} // coord is the room the wall is dug from
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := mazelib.Coordinate{x, y}
+			if m.Solid(x, y) {
+				continue
+			}
+			r, _ := m.GetRoom(x, y)
+			for _, p := range neighbors(m, c) {
+				if p.dir != "right" && p.dir != "down" {
+					continue
+				}
+				if blocked(r.Walls, p.dir) {
+					walls = append(walls, possibility{p.dir, c})
+				} else {
+					sets.Union(index(c), index(p.coord))
+				}
+			}
+		}
+	}
+	rng.Shuffle(len(walls), func(i, j int) { walls[i], walls[j] = walls[j], walls[i] })
+	for _, w := range walls {
+		to := w.coord
+		if w.dir == "right" {
+			to.X++
+		} else {
+			to.Y++
+		}
+		if sets.Union(index(w.coord), index(to)) {
+			digInto(w.dir, w.coord, m)
+		}
+	}
+}
+
+// How many rooms of the maze aren't solid.
+func openRooms(m *mazelib.Maze) int {
+	if k := m.Mask(); k != nil {
+		return k.Open()
+	}
+	return m.Width() * m.Height()
+}
+
+// A random room that isn't solid.
+func randomRoom(m *mazelib.Maze, rng *rand.Rand) mazelib.Coordinate {
+	for {
+		c := mazelib.Coordinate{rng.Intn(m.Width()), rng.Intn(m.Height())}
+		if !m.Solid(c.X, c.Y) {
+			return c
+		}
+	}
+}
+
+// The open room closest to c, counting steps through solid rock too.
+// Ties go to whichever is found first.
+func nearestOpen(m *mazelib.Maze, c mazelib.Coordinate) mazelib.Coordinate {
+	seen := map[mazelib.Coordinate]bool{c: true}
+	queue := []mazelib.Coordinate{c}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		if !m.Solid(n.X, n.Y) {
+			return n
+		}
+		for _, p := range []mazelib.Coordinate{{n.X - 1, n.Y}, {n.X + 1, n.Y}, {n.X, n.Y - 1}, {n.X, n.Y + 1}} {
+			if p.X >= 0 && p.Y >= 0 && p.X < m.Width() && p.Y < m.Height() && !seen[p] {
+				seen[p] = true
+				queue = append(queue, p)
+			}
+		}
+	}
+	return c
+}
@@ -0,0 +1,115 @@
+package generators
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/golangchallenge/gc6/mazelib"
+)
+
+// Options for where a generator puts the start and treasure.
+// Add these to a Registration's Options and wrap the generator with placed.
+var placementOptions = map[string]string{
+	"start":    "random (default), corner (top left) or center",
+	"treasure": "random (default), corner (bottom right) or farthest (from the start)",
+}
+
+// Wrap g so the start and treasure are moved as opts ask once the maze is carved.
+// With neither option set g is returned as is, keeping the generator's own placement.
+func placed(g MaskedGenerator, opts Options) (Generator, error) {
+	start, treasure := opts["start"], opts["treasure"]
+	switch start {
+	case "", "random", "corner", "center":
+	default:
+		return nil, fmt.Errorf("unknown start placement %q. Choose random, corner or center", start)
+	}
+	switch treasure {
+	case "", "random", "corner", "farthest":
+	default:
+		return nil, fmt.Errorf("unknown treasure placement %q. Choose random, corner or farthest", treasure)
+	}
+	if (start == "" || start == "random") && (treasure == "" || treasure == "random") {
+		return g, nil
+	}
+	return placedGenerator{g, start, treasure}, nil
+}
+
+// A MaskedGenerator that moves the start and treasure of every maze it makes.
+type placedGenerator struct {
+	g               MaskedGenerator
+	start, treasure string
+}
+
+func (p placedGenerator) Generate(width, height int, rng *rand.Rand) *mazelib.Maze {
+	m := p.g.Generate(width, height, rng)
+	place(m, p.start, p.treasure)
+	return m
+}
+
+func (p placedGenerator) GenerateMasked(k *mazelib.Mask, rng *rand.Rand) *mazelib.Maze {
+	m := p.g.GenerateMasked(k, rng)
+	place(m, p.start, p.treasure)
+	return m
+}
+
+// Move the start and treasure of a carved maze.
+// Corners and the center may be solid in a masked maze, in which case the
+// nearest open room is used. A treasure that would land on the start is
+// put in the room farthest from it instead.
+func place(m *mazelib.Maze, start, treasure string) {
+	sx, sy := m.Start()
+	s := mazelib.Coordinate{sx, sy}
+	switch start {
+	case "corner":
+		s = nearestOpen(m, mazelib.Coordinate{0, 0})
+	case "center":
+		s = nearestOpen(m, mazelib.Coordinate{m.Width() / 2, m.Height() / 2})
+	}
+	ex, ey := m.End()
+	t := mazelib.Coordinate{ex, ey}
+	switch treasure {
+	case "corner":
+		t = nearestOpen(m, mazelib.Coordinate{m.Width() - 1, m.Height() - 1})
+	case "farthest":
+		t = farthest(m, s)
+	}
+	if t == s {
+		t = farthest(m, s)
+	}
+	m.MoveStartAndEnd(s, t)
+}
+
+// The room that takes the most moves to reach from c.
+// Ties go to whichever is found first.
+func farthest(m *mazelib.Maze, c mazelib.Coordinate) mazelib.Coordinate {
+	seen := map[mazelib.Coordinate]bool{c: true}
+	queue := []mazelib.Coordinate{c}
+	last := c
+	for len(queue) > 0 {
+		last = queue[0]
+		queue = queue[1:]
+		r, _ := m.GetRoom(last.X, last.Y)
+		for _, p := range neighbors(m, last) {
+			if !seen[p.coord] && !blocked(r.Walls, p.dir) {
+				seen[p.coord] = true
+				queue = append(queue, p.coord)
+			}
+		}
+	}
+	return last
+}
+
+// Is there a wall on the given side of a room?
+func blocked(w mazelib.Survey, dir string) bool {
+	switch dir {
+	case "left":
+		return w.Left
+	case "right":
+		return w.Right
+	case "up":
+		return w.Top
+	case "down":
+		return w.Bottom
+	}
+	return true
+}
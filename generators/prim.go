@@ -0,0 +1,61 @@
+package generators
+
+import (
+	"math/rand"
+
+	"github.com/golangchallenge/gc6/mazelib"
+)
+
+func init() {
+	Register(Registration{
+		Name:        "prim",
+		Description: "Prim's (Short, bushy dead ends)",
+		Options:     placementOptions,
+		New: func(opts Options) (Generator, error) {
+			return placed(carver{carve: prim}, opts)
+		},
+	})
+}
+
+// Create a new maze with randomized Prim's algorithm.
+// The maze grows outward from a random room. Each step picks a random room
+// bordering the maze and joins it to a random neighbor already in the maze.
+// Unlike DepthFirst this gives lots of short dead ends branching off
+// everywhere instead of long corridors.
+// All random choices are made with rng.
+func Prim(width, height int, rng *rand.Rand) *mazelib.Maze {
+	return carver{carve: prim}.Generate(width, height, rng)
+}
+
+// Carve a Prim's maze into m, which starts with every wall up.
+func prim(m *mazelib.Maze, rng *rand.Rand) {
+	in := map[mazelib.Coordinate]bool{}
+	inFrontier := map[mazelib.Coordinate]bool{}
+	frontier := []mazelib.Coordinate{}
+	add := func(c mazelib.Coordinate) {
+		in[c] = true
+		for _, p := range neighbors(m, c) {
+			if !in[p.coord] && !inFrontier[p.coord] {
+				inFrontier[p.coord] = true
+				frontier = append(frontier, p.coord)
+			}
+		}
+	}
+
+	add(randomRoom(m, rng))
+	for len(frontier) > 0 {
+		i := rng.Intn(len(frontier))
+		c := frontier[i]
+		frontier[i] = frontier[len(frontier)-1]
+		frontier = frontier[:len(frontier)-1]
+
+		possible := []possibility{}
+		for _, p := range neighbors(m, c) {
+			if in[p.coord] {
+				possible = append(possible, p)
+			}
+		}
+		digInto(possible[rng.Intn(len(possible))].dir, c, m)
+		add(c)
+	}
+}
@@ -0,0 +1,95 @@
+package generators
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/golangchallenge/gc6/mazelib"
+)
+
+// Generator creates new mazes of a given size.
+// Every random choice must come from rng, so the same seed always gives the same maze.
+type Generator interface {
+	Generate(width, height int, rng *rand.Rand) *mazelib.Maze
+}
+
+// GeneratorFunc lets a plain function be used as a Generator.
+type GeneratorFunc func(width, height int, rng *rand.Rand) *mazelib.Maze
+
+func (f GeneratorFunc) Generate(width, height int, rng *rand.Rand) *mazelib.Maze {
+	return f(width, height, rng)
+}
+
+// Options configure a generator when it is created by name.
+// Keys are the option names listed in the generator's Registration.
+type Options map[string]string
+
+// Registration describes a generator that can be created by name.
+type Registration struct {
+	Name        string
+	Description string
+	// Options the generator understands, mapped to a description of each.
+	Options map[string]string
+	// Create the generator. opts only contains keys listed in Options.
+	New func(opts Options) (Generator, error)
+}
+
+var registry = map[string]Registration{}
+var registryOrder = []string{}
+
+// Register makes a generator available by name.
+// Registering the same name twice panics.
+func Register(r Registration) {
+	if _, dup := registry[r.Name]; dup {
+		panic("generators: Register called twice for " + r.Name)
+	}
+	registry[r.Name] = r
+	registryOrder = append(registryOrder, r.Name)
+}
+
+// Lookup finds a registered generator by name.
+func Lookup(name string) (Registration, bool) {
+	r, ok := registry[name]
+	return r, ok
+}
+
+// List returns every registered generator, in the order they were registered.
+func List() []Registration {
+	all := make([]Registration, 0, len(registryOrder))
+	for _, name := range registryOrder {
+		all = append(all, registry[name])
+	}
+	return all
+}
+
+// New creates the named generator with the given options.
+// Options the generator does not understand are an error.
+func New(name string, opts Options) (Generator, error) {
+	r, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown generator %q. Choose one of: %s", name, strings.Join(registryOrder, ", "))
+	}
+	unknown := []string{}
+	for k := range opts {
+		if _, ok := r.Options[k]; !ok {
+			unknown = append(unknown, k)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("generator %q does not understand option(s): %s", name, strings.Join(unknown, ", "))
+	}
+	return r.New(opts)
+}
+
+func init() {
+	Register(Registration{
+		Name:        "empty",
+		Description: "Empty",
+		New: func(Options) (Generator, error) {
+			return carver{additive: true}, nil
+		},
+	})
+}
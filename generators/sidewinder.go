@@ -0,0 +1,99 @@
+package generators
+
+import (
+	"math/rand"
+
+	"github.com/golangchallenge/gc6/mazelib"
+)
+
+func init() {
+	options := map[string]string{"corner": "NE (default), NW, SE or SW. Runs head along the row towards it, then join the row before"}
+	for k, v := range placementOptions {
+		options[k] = v
+	}
+	Register(Registration{
+		Name:        "sidewinder",
+		Description: "Sidewinder (Open edge, vertical bias)",
+		Options:     options,
+		New: func(opts Options) (Generator, error) {
+			corner := opts["corner"]
+			if _, _, err := cornerDirs(corner); err != nil {
+				return nil, err
+			}
+			return placed(carver{carve: func(m *mazelib.Maze, rng *rand.Rand) { sidewinder(m, corner, rng) }}, opts)
+		},
+	})
+}
+
+// Create a new maze with the sidewinder algorithm.
+// The row along the corner's edge (the top for NE and NW) is one open corridor.
+// Every other row is cut into runs heading horizontally towards the corner,
+// and each run gets a single passage from a random room in it to the row
+// nearer the corner. Like BinaryTree the corner may be NE, NW, SE or SW,
+// with empty or unknown meaning NE.
+// All random choices are made with rng.
+func Sidewinder(width, height int, corner string, rng *rand.Rand) *mazelib.Maze {
+	return carver{carve: func(m *mazelib.Maze, rng *rand.Rand) { sidewinder(m, corner, rng) }}.Generate(width, height, rng)
+}
+
+// Carve a sidewinder maze into m, which starts with every wall up.
+// Solid rooms break rows into separate runs.
+func sidewinder(m *mazelib.Maze, corner string, rng *rand.Rand) {
+	width, height := m.Width(), m.Height()
+	v, h, err := cornerDirs(corner)
+	if err != nil {
+		v, h, _ = cornerDirs("")
+	}
+	// Walk rows starting from the corner's edge, and each row away from the corner
+	row := func(i int) int { return i }
+	if v == "down" {
+		row = func(i int) int { return height - 1 - i }
+	}
+	col := func(j int) int { return j }
+	if h == "left" {
+		col = func(j int) int { return width - 1 - j }
+	}
+
+	run := []mazelib.Coordinate{}
+	for i := 0; i < height; i++ {
+		run = run[:0]
+		for j := 0; j < width; j++ {
+			c := mazelib.Coordinate{col(j), row(i)}
+			if m.Solid(c.X, c.Y) {
+				run = run[:0]
+				continue
+			}
+			run = append(run, c)
+			last := !canDig(m, c, h)
+			if i == 0 {
+				if !last {
+					digInto(h, c, m)
+				}
+				continue
+			}
+			if !last && rng.Intn(2) == 0 {
+				digInto(h, c, m)
+				continue
+			}
+			up := run
+			if m.Mask() != nil {
+				up = []mazelib.Coordinate{}
+				for _, r := range run {
+					if canDig(m, r, v) {
+						up = append(up, r)
+					}
+				}
+			}
+			if len(up) == 0 {
+				// Nowhere to join the row before. Keep the run going if possible
+				if !last {
+					digInto(h, c, m)
+					continue
+				}
+			} else {
+				digInto(v, up[rng.Intn(len(up))], m)
+			}
+			run = run[:0]
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package generators
+
+// UnionFind tracks which of n items have been joined into the same set.
+// Items are numbered 0 to n-1 and start out each in a set of their own.
+type UnionFind struct {
+	parent []int
+	size   []int
+	sets   int
+}
+
+// NewUnionFind creates n separate sets.
+func NewUnionFind(n int) *UnionFind {
+	u := &UnionFind{parent: make([]int, n), size: make([]int, n), sets: n}
+	for i := range u.parent {
+		u.parent[i] = i
+		u.size[i] = 1
+	}
+	return u
+}
+
+// Find returns the representative of the set containing i.
+// Two items are in the same set when Find returns the same value for both.
+func (u *UnionFind) Find(i int) int {
+	for u.parent[i] != i {
+		// Point every other item on the way at its grandparent, keeping trees flat
+		u.parent[i] = u.parent[u.parent[i]]
+		i = u.parent[i]
+	}
+	return i
+}
+
+// Union joins the sets containing a and b.
+// It returns false if they were already the same set.
+func (u *UnionFind) Union(a, b int) bool {
+	a, b = u.Find(a), u.Find(b)
+	if a == b {
+		return false
+	}
+	if u.size[a] < u.size[b] {
+		a, b = b, a
+	}
+	u.parent[b] = a
+	u.size[a] += u.size[b]
+	u.sets--
+	return true
+}
+
+// Connected reports whether a and b are in the same set.
+func (u *UnionFind) Connected(a, b int) bool {
+	return u.Find(a) == u.Find(b)
+}
+
+// Sets returns how many separate sets remain.
+func (u *UnionFind) Sets() int {
+	return u.sets
+}
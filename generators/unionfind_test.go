@@ -0,0 +1,70 @@
+package generators
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestUnionFind(t *testing.T) {
+	u := NewUnionFind(5)
+	if u.Sets() != 5 {
+		t.Fatalf("Sets() = %d, want 5", u.Sets())
+	}
+	for _, c := range []struct {
+		a, b   int
+		joined bool
+		sets   int
+	}{
+		{0, 1, true, 4},
+		{1, 0, false, 4},
+		{2, 3, true, 3},
+		{1, 3, true, 2},
+		{0, 2, false, 2},
+		{4, 4, false, 2},
+	} {
+		if got := u.Union(c.a, c.b); got != c.joined {
+			t.Errorf("Union(%d, %d) = %v, want %v", c.a, c.b, got, c.joined)
+		}
+		if u.Sets() != c.sets {
+			t.Errorf("after Union(%d, %d) Sets() = %d, want %d", c.a, c.b, u.Sets(), c.sets)
+		}
+	}
+	if !u.Connected(0, 3) || u.Connected(0, 4) {
+		t.Errorf("Connected(0, 3) = %v, Connected(0, 4) = %v, want true, false", u.Connected(0, 3), u.Connected(0, 4))
+	}
+}
+
+// Compare against relabelling every item of the smaller set, which is slow but obviously right.
+func TestUnionFindRandom(t *testing.T) {
+	const n = 200
+	rng := rand.New(rand.NewSource(1))
+	u := NewUnionFind(n)
+	label := make([]int, n)
+	for i := range label {
+		label[i] = i
+	}
+	sets := n
+	for i := 0; i < 500; i++ {
+		a, b := rng.Intn(n), rng.Intn(n)
+		want := label[a] != label[b]
+		if want {
+			from, to := label[b], label[a]
+			for j := range label {
+				if label[j] == from {
+					label[j] = to
+				}
+			}
+			sets--
+		}
+		if got := u.Union(a, b); got != want {
+			t.Fatalf("Union(%d, %d) = %v, want %v", a, b, got, want)
+		}
+		if u.Sets() != sets {
+			t.Fatalf("Sets() = %d, want %d", u.Sets(), sets)
+		}
+		x, y := rng.Intn(n), rng.Intn(n)
+		if got := u.Connected(x, y); got != (label[x] == label[y]) {
+			t.Fatalf("Connected(%d, %d) = %v, want %v", x, y, got, !got)
+		}
+	}
+}
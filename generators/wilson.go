@@ -0,0 +1,95 @@
+package generators
+
+import (
+	"math/rand"
+
+	"github.com/golangchallenge/gc6/mazelib"
+)
+
+func init() {
+	Register(Registration{
+		Name:        "wilson",
+		Description: "Wilson's (Uniform spanning tree)",
+		Options:     placementOptions,
+		New: func(opts Options) (Generator, error) {
+			return placed(carver{carve: wilson}, opts)
+		},
+	})
+	Register(Registration{
+		Name:        "aldous-broder",
+		Description: "Aldous-Broder (Uniform spanning tree, slow)",
+		Options:     placementOptions,
+		New: func(opts Options) (Generator, error) {
+			return placed(carver{carve: aldousBroder}, opts)
+		},
+	})
+}
+
+// Create a new maze with Wilson's algorithm.
+// Every perfect maze of the given size is equally likely, which makes these
+// mazes a fair benchmark for comparing solvers.
+// Starting from a single random room, each room not yet in the maze does a
+// random walk until it hits the maze. Any loops the walk made are forgotten
+// and what is left of the path is dug out and joined to the maze.
+// All random choices are made with rng.
+func Wilson(width, height int, rng *rand.Rand) *mazelib.Maze {
+	return carver{carve: wilson}.Generate(width, height, rng)
+}
+
+// Carve a Wilson's maze into m, which starts with every wall up.
+// The open rooms must all be connected, or the walks never end.
+func wilson(m *mazelib.Maze, rng *rand.Rand) {
+	width, height := m.Width(), m.Height()
+	in := map[mazelib.Coordinate]bool{
+		randomRoom(m, rng): true,
+	}
+	// The direction each room was last left in during the current walk.
+	// Following these from the start of the walk skips every loop.
+	exit := map[mazelib.Coordinate]possibility{}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			start := mazelib.Coordinate{x, y}
+			if in[start] || m.Solid(x, y) {
+				continue
+			}
+			for c := start; !in[c]; {
+				possible := neighbors(m, c)
+				p := possible[rng.Intn(len(possible))]
+				exit[c] = p
+				c = p.coord
+			}
+			for c := start; !in[c]; {
+				in[c] = true
+				digInto(exit[c].dir, c, m)
+				c = exit[c].coord
+			}
+		}
+	}
+}
+
+// Create a new maze with the Aldous-Broder algorithm.
+// Like Wilson, every perfect maze is equally likely, but it is much slower
+// to finish. It is kept as a simple reference to check Wilson against.
+// A single random walk wanders the whole grid, digging into each room the
+// first time it is entered, until every room has been visited.
+// All random choices are made with rng.
+func AldousBroder(width, height int, rng *rand.Rand) *mazelib.Maze {
+	return carver{carve: aldousBroder}.Generate(width, height, rng)
+}
+
+// Carve an Aldous-Broder maze into m, which starts with every wall up.
+// The open rooms must all be connected, or the walk never ends.
+func aldousBroder(m *mazelib.Maze, rng *rand.Rand) {
+	c := randomRoom(m, rng)
+	visited := map[mazelib.Coordinate]bool{c: true}
+	open := openRooms(m)
+	for len(visited) < open {
+		possible := neighbors(m, c)
+		p := possible[rng.Intn(len(possible))]
+		if !visited[p.coord] {
+			visited[p.coord] = true
+			digInto(p.dir, c, m)
+		}
+		c = p.coord
+	}
+}
@@ -0,0 +1,54 @@
+package generators
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/golangchallenge/gc6/mazelib"
+)
+
+// Every spanning tree of a tiny grid should come up about equally often.
+func TestUniformSpanningTrees(t *testing.T) {
+	for _, c := range []struct {
+		width, height int
+		trees         int
+		// 99.9th percentile of the chi-square distribution with trees-1 degrees of freedom
+		bound float64
+	}{
+		{2, 2, 4, 16.27},
+		{3, 2, 15, 36.12},
+	} {
+		for _, g := range []struct {
+			name     string
+			generate func(width, height int, rng *rand.Rand) *mazelib.Maze
+		}{
+			{"wilson", Wilson},
+			{"aldous-broder", AldousBroder},
+		} {
+			const perTree = 1000
+			rng := rand.New(rand.NewSource(1))
+			counts := map[string]int{}
+			for i := 0; i < perTree*c.trees; i++ {
+				m := g.generate(c.width, c.height, rng)
+				passages, _ := interior(m)
+				if len(passages) != c.width*c.height-1 || len(m.Validate()) > 0 {
+					t.Fatalf("%s %dx%d: not a perfect maze\n%v", g.name, c.width, c.height, passages)
+				}
+				counts[fmt.Sprint(passages)]++
+			}
+			if len(counts) != c.trees {
+				t.Errorf("%s %dx%d: made %d different mazes, want %d", g.name, c.width, c.height, len(counts), c.trees)
+				continue
+			}
+			chi2 := 0.0
+			for _, n := range counts {
+				d := float64(n - perTree)
+				chi2 += d * d / perTree
+			}
+			if chi2 > c.bound {
+				t.Errorf("%s %dx%d: chi-square %.1f is over %.1f, counts %v", g.name, c.width, c.height, chi2, c.bound, counts)
+			}
+		}
+	}
+}
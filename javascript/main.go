@@ -13,7 +13,9 @@ import (
 
 //go:generate gopherjs build main.go
 func main() {
-	rand.Seed(time.Now().UnixNano())
+	clearOptions("generator")
+	clearOptions("solver")
+	setupGenerators()
 	setupEvents()
 	initialize()
 	generators.Animate = AnimateGeneration
@@ -21,30 +23,57 @@ func main() {
 
 var currentContext *renderData
 
+// source of randomness for both generating and solving
+var rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+
 func initialize() {
 	currentContext = &renderData{}
 	currentContext.maze = idToGenerator()
-	currentContext.solver = solvers.NewDFS()
+	currentContext.solver = idToSolver()
 	render(currentContext)
 }
 
 func idToGenerator() *mazelib.Maze {
 	val := dom.GetWindow().Document().GetElementByID("generator").(*dom.HTMLSelectElement).Value
-	switch val {
-	case "dfs":
-		return generators.DepthFirst(15, 10, "")
-	case "dfs-h":
-		return generators.DepthFirst(15, 10, "H")
-	case "dfs-v":
-		return generators.DepthFirst(15, 10, "V")
-	case "dfs-x":
-		return generators.DepthFirst(15, 10, "X")
-	case "dfs-o":
-		return generators.DepthFirst(15, 10, "O")
-	case "empty":
-		return mazelib.EmptyMaze(15, 10)
+	g, err := generators.New(val, nil)
+	if err != nil {
+		panic(err)
+	}
+	return g.Generate(15, 10, rng)
+}
+
+func idToSolver() solvers.MazeSolver {
+	val := dom.GetWindow().Document().GetElementByID("solver").(*dom.HTMLSelectElement).Value
+	s, err := solvers.New(val, rng)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// fill the generator and solver dropdowns from their registries
+func setupGenerators() {
+	for _, r := range generators.List() {
+		addOption("generator", r.Name, r.Description)
+	}
+	for _, r := range solvers.List() {
+		addOption("solver", r.Name, r.Description)
 	}
-	panic("unknown generator")
+	// start on the same generator labyrinth uses by default
+	dom.GetWindow().Document().GetElementByID("generator").(*dom.HTMLSelectElement).Value = "dfs-o"
+}
+
+func clearOptions(selectID string) {
+	dom.GetWindow().Document().GetElementByID(selectID).(*dom.HTMLSelectElement).SetInnerHTML("")
+}
+
+func addOption(selectID, value, text string) {
+	doc := dom.GetWindow().Document()
+	sel := doc.GetElementByID(selectID).(*dom.HTMLSelectElement)
+	opt := doc.CreateElement("option").(*dom.HTMLOptionElement)
+	opt.Value = value
+	opt.SetTextContent(text)
+	sel.AppendChild(opt)
 }
 
 func setupEvents() {
@@ -82,18 +111,7 @@ func step() error {
 		fmt.Println(err)
 		return err
 	}
-	dir := c.solver.Step(surv)
-	switch dir {
-	case "left":
-		err = c.maze.MoveLeft()
-	case "right":
-		err = c.maze.MoveRight()
-	case "down":
-		err = c.maze.MoveDown()
-	case "up":
-		err = c.maze.MoveUp()
-	}
-	if err != nil {
+	if err = c.maze.Move(c.solver.Step(surv)); err != nil {
 		fmt.Println(err)
 		return err
 	}
@@ -119,6 +137,10 @@ func render(c *renderData) {
 	curX, curY := c.maze.Icarus()
 	for y := 0; y < c.maze.Height(); y++ {
 		for x := 0; x < c.maze.Width(); x++ {
+			if c.maze.Solid(x, y) {
+				fillCell(ctx, x, y, "gray")
+				continue
+			}
 			fillCell(ctx, x, y, "white")
 			if x == curX && y == curY {
 				fillCell(ctx, x, y, "pink")
@@ -134,7 +156,7 @@ func render(c *renderData) {
 
 func fillCell(ctx *dom.CanvasRenderingContext2D, x, y int, color string) {
 	ctx.FillStyle = color
-	ctx.FillRect(x*cellWidth+2, y*cellWidth+2, cellWidth-4, cellWidth-4)
+	fillRect(ctx, x*cellWidth+2, y*cellWidth+2, cellWidth-4, cellWidth-4)
 }
 
 func drawBorders(c *renderData, ctx *dom.CanvasRenderingContext2D, x, y int) {
@@ -146,27 +168,32 @@ func drawBorders(c *renderData, ctx *dom.CanvasRenderingContext2D, x, y int) {
 		if y == 0 {
 			height = 4
 		}
-		ctx.FillRect(x*cellWidth-2, y*cellWidth, cellWidth+4, height)
+		fillRect(ctx, x*cellWidth-2, y*cellWidth, cellWidth+4, height)
 	}
 	if cell.Walls.Bottom {
 		height := 2
 		if y == c.maze.Height()-1 {
 			height = 4
 		}
-		ctx.FillRect(x*cellWidth-2, y*cellWidth+(cellWidth-height), cellWidth+4, height)
+		fillRect(ctx, x*cellWidth-2, y*cellWidth+(cellWidth-height), cellWidth+4, height)
 	}
 	if cell.Walls.Left {
 		width := 2
 		if x == 0 {
 			width = 4
 		}
-		ctx.FillRect(x*cellWidth, y*cellWidth-2, width, cellWidth+4)
+		fillRect(ctx, x*cellWidth, y*cellWidth-2, width, cellWidth+4)
 	}
 	if cell.Walls.Right {
 		width := 2
 		if x == c.maze.Width()-1 {
 			width = 4
 		}
-		ctx.FillRect(x*cellWidth+(cellWidth-width), y*cellWidth-2, width, cellWidth+4)
+		fillRect(ctx, x*cellWidth+(cellWidth-width), y*cellWidth-2, width, cellWidth+4)
 	}
 }
+
+// FillRect takes float64s, but the maze is laid out in whole pixels
+func fillRect(ctx *dom.CanvasRenderingContext2D, x, y, width, height int) {
+	ctx.FillRect(float64(x), float64(y), float64(width), float64(height))
+}
@@ -0,0 +1,281 @@
+// Package analysis computes difficulty metrics for mazes,
+// so generator biases can be tuned with numbers instead of by eye.
+//
+// The maze is treated as a graph with a node per room and an edge between
+// neighboring rooms when there is no wall on either side of the passage.
+// Solid rooms of a masked maze are left out entirely.
+package analysis
+
+import (
+	"math/rand"
+
+	"github.com/golangchallenge/gc6/mazelib"
+)
+
+// Number of simulated runs used to estimate the DFS step count when the maze has loops.
+const dfsTrials = 1000
+
+// Report holds every metric for a single maze.
+type Report struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+	// Fewest moves from the start to the treasure, or -1 if it can't be reached.
+	ShortestPath int `json:"shortestPath"`
+	// Rooms with exactly one way out.
+	DeadEnds int `json:"deadEnds"`
+	// Rooms with three or more ways out.
+	Junctions int `json:"junctions"`
+	// Degrees[n] is the number of rooms with n ways out.
+	Degrees [5]int `json:"degrees"`
+	// Most rooms in a row with exactly two ways out, i.e. with no choice to make.
+	LongestCorridor int `json:"longestCorridor"`
+	// Number of independent loops. Zero for a perfect maze.
+	Cyclomatic int `json:"cyclomatic"`
+	// Number of connected groups of rooms.
+	Components int `json:"components"`
+	// Expected moves for a randomized depth first solver to find the treasure.
+	ExpectedDFSSteps float64 `json:"expectedDfsSteps"`
+	// Whether ExpectedDFSSteps is exact, or estimated by simulation because the maze has loops.
+	ExpectedDFSExact bool `json:"expectedDfsExact"`
+}
+
+type graph struct {
+	width, height int
+	adj           [][]int // neighbors of each room, by index y*width+x
+	solid         []bool
+}
+
+func (g *graph) index(c mazelib.Coordinate) int { return c.Y*g.width + c.X }
+
+func newGraph(m *mazelib.Maze) *graph {
+	g := &graph{width: m.Width(), height: m.Height()}
+	g.adj = make([][]int, g.width*g.height)
+	g.solid = make([]bool, g.width*g.height)
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			r, _ := m.GetRoom(x, y)
+			i := y*g.width + x
+			if m.Solid(x, y) {
+				g.solid[i] = true
+				continue
+			}
+			if x < g.width-1 && !m.Solid(x+1, y) {
+				right, _ := m.GetRoom(x+1, y)
+				if !r.Walls.Right && !right.Walls.Left {
+					g.adj[i] = append(g.adj[i], i+1)
+					g.adj[i+1] = append(g.adj[i+1], i)
+				}
+			}
+			if y < g.height-1 && !m.Solid(x, y+1) {
+				below, _ := m.GetRoom(x, y+1)
+				if !r.Walls.Bottom && !below.Walls.Top {
+					g.adj[i] = append(g.adj[i], i+g.width)
+					g.adj[i+g.width] = append(g.adj[i+g.width], i)
+				}
+			}
+		}
+	}
+	return g
+}
+
+// Analyze computes every metric for the maze.
+// rng is only used when the DFS step count has to be estimated by simulation.
+func Analyze(m *mazelib.Maze, rng *rand.Rand) Report {
+	g := newGraph(m)
+	sx, sy := m.Start()
+	ex, ey := m.End()
+	start, end := g.index(mazelib.Coordinate{X: sx, Y: sy}), g.index(mazelib.Coordinate{X: ex, Y: ey})
+
+	r := Report{Width: g.width, Height: g.height}
+	edges, rooms := 0, 0
+	for i, n := range g.adj {
+		if g.solid[i] {
+			continue
+		}
+		rooms++
+		d := len(n)
+		r.Degrees[d]++
+		edges += d
+		if d == 1 {
+			r.DeadEnds++
+		}
+		if d >= 3 {
+			r.Junctions++
+		}
+	}
+	edges /= 2
+
+	r.Components = g.components()
+	r.Cyclomatic = edges - rooms + r.Components
+	r.LongestCorridor = g.longestCorridor()
+	r.ShortestPath = g.distance(start, end)
+
+	if r.ShortestPath < 0 {
+		r.ExpectedDFSSteps = -1
+		r.ExpectedDFSExact = true
+	} else if nodes, edges := g.componentSize(start); edges == nodes-1 {
+		// Without loops the answer barely depends on the layout.
+		// At every room on the path to the treasure, each side branch is
+		// explored first with probability 1/2, costing two moves per room in
+		// it. Adding the path itself, every room but the treasure costs one
+		// move on average, except the rooms beyond the treasure, which are
+		// never reached.
+		r.ExpectedDFSSteps = float64(g.countBefore(start, end) - 1)
+		r.ExpectedDFSExact = true
+	} else {
+		r.ExpectedDFSSteps = g.simulateDFS(start, end, rng)
+	}
+	return r
+}
+
+func (g *graph) components() int {
+	seen := make([]bool, len(g.adj))
+	count := 0
+	for i := range g.adj {
+		if seen[i] || g.solid[i] {
+			continue
+		}
+		count++
+		stack := []int{i}
+		seen[i] = true
+		for len(stack) > 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, o := range g.adj[n] {
+				if !seen[o] {
+					seen[o] = true
+					stack = append(stack, o)
+				}
+			}
+		}
+	}
+	return count
+}
+
+// Breadth first distance between two rooms, -1 if unreachable.
+func (g *graph) distance(from, to int) int {
+	dist := make([]int, len(g.adj))
+	for i := range dist {
+		dist[i] = -1
+	}
+	dist[from] = 0
+	queue := []int{from}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		if n == to {
+			return dist[n]
+		}
+		for _, o := range g.adj[n] {
+			if dist[o] < 0 {
+				dist[o] = dist[n] + 1
+				queue = append(queue, o)
+			}
+		}
+	}
+	return -1
+}
+
+// Walk every chain of degree 2 rooms, returning the longest.
+func (g *graph) longestCorridor() int {
+	seen := make([]bool, len(g.adj))
+	longest := 0
+	for i := range g.adj {
+		if seen[i] || len(g.adj[i]) != 2 {
+			continue
+		}
+		seen[i] = true
+		length := 1
+		// follow the corridor out of both ends
+		for _, next := range g.adj[i] {
+			prev := i
+			for len(g.adj[next]) == 2 && !seen[next] {
+				seen[next] = true
+				length++
+				a, b := g.adj[next][0], g.adj[next][1]
+				if a == prev {
+					prev, next = next, b
+				} else {
+					prev, next = next, a
+				}
+			}
+		}
+		if length > longest {
+			longest = length
+		}
+	}
+	return longest
+}
+
+// Count the rooms and passages in the component containing root.
+func (g *graph) componentSize(root int) (nodes, edges int) {
+	seen := make([]bool, len(g.adj))
+	seen[root] = true
+	stack := []int{root}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		nodes++
+		edges += len(g.adj[n])
+		for _, o := range g.adj[n] {
+			if !seen[o] {
+				seen[o] = true
+				stack = append(stack, o)
+			}
+		}
+	}
+	return nodes, edges / 2
+}
+
+// Count the rooms that can be reached from root without passing through stop, including stop.
+func (g *graph) countBefore(root, stop int) int {
+	seen := make([]bool, len(g.adj))
+	seen[root] = true
+	stack := []int{root}
+	count := 0
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		count++
+		if n == stop {
+			continue
+		}
+		for _, o := range g.adj[n] {
+			if !seen[o] {
+				seen[o] = true
+				stack = append(stack, o)
+			}
+		}
+	}
+	return count
+}
+
+// Estimate the expected steps of a randomized DFS by running it many times.
+// Mirrors solvers.NewDFS: move to a random unvisited neighbor, backtrack when there is none.
+func (g *graph) simulateDFS(start, end int, rng *rand.Rand) float64 {
+	total := 0
+	for t := 0; t < dfsTrials; t++ {
+		visited := map[int]bool{start: true}
+		path := []int{start}
+		steps := 0
+		for path[len(path)-1] != end {
+			n := path[len(path)-1]
+			options := []int{}
+			for _, o := range g.adj[n] {
+				if !visited[o] {
+					options = append(options, o)
+				}
+			}
+			steps++
+			if len(options) == 0 {
+				path = path[:len(path)-1]
+				continue
+			}
+			next := options[rng.Intn(len(options))]
+			visited[next] = true
+			path = append(path, next)
+		}
+		total += steps
+	}
+	return float64(total) / dfsTrials
+}
@@ -0,0 +1,69 @@
+package analysis
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/golangchallenge/gc6/generators"
+	"github.com/golangchallenge/gc6/mazelib"
+)
+
+func parse(t *testing.T, s string) *mazelib.Maze {
+	t.Helper()
+	m, err := mazelib.ParseASCII(strings.NewReader(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestExpectedDFSStepsPerfectMaze(t *testing.T) {
+	for _, c := range []struct {
+		maze string
+		want float64
+	}{
+		// the rooms beyond the treasure are never visited
+		{"_________\n|S̲ T̲ _ _|\n", 1},
+		// a dead end behind the start is tried first half the time, costing two moves
+		{"_________\n|_ S̲ T̲ _|\n", 2},
+		{"_________\n|_ _ S̲ T̲|\n", 3},
+		// the side branch off the path is one room, the room above T is beyond it
+		{`
+			_______
+			|S  | |
+			|_|T̲ _|
+		`, 3},
+	} {
+		r := Analyze(parse(t, c.maze), rand.New(rand.NewSource(1)))
+		if !r.ExpectedDFSExact || r.ExpectedDFSSteps != c.want {
+			t.Errorf("%s\nExpectedDFSSteps = %v (exact %v), want %v (exact)", c.maze, r.ExpectedDFSSteps, r.ExpectedDFSExact, c.want)
+		}
+	}
+}
+
+// The exact answer for perfect mazes should agree with simulating the solver.
+func TestExpectedDFSStepsMatchesSimulation(t *testing.T) {
+	for seed := int64(1); seed <= 5; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		m := generators.DepthFirst(8, 6, "", rng)
+		r := Analyze(m, rng)
+		if !r.ExpectedDFSExact {
+			t.Fatalf("seed %d: a depth first maze has no loops, the answer should be exact", seed)
+		}
+
+		g := newGraph(m)
+		sx, sy := m.Start()
+		ex, ey := m.End()
+		start, end := g.index(mazelib.Coordinate{X: sx, Y: sy}), g.index(mazelib.Coordinate{X: ex, Y: ey})
+		sim := 0.0
+		const runs = 20
+		for i := 0; i < runs; i++ {
+			sim += g.simulateDFS(start, end, rng) / runs
+		}
+		if math.Abs(sim-r.ExpectedDFSSteps) > 0.05*r.ExpectedDFSSteps {
+			t.Errorf("seed %d: ExpectedDFSSteps = %.1f, but simulation averages %.1f", seed, r.ExpectedDFSSteps, sim)
+		}
+	}
+}
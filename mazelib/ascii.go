@@ -0,0 +1,178 @@
+package mazelib
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The combining low line PrintMaze puts after S and T when the room has a bottom wall.
+const combiningUnderline = '\u0332'
+
+// ParseError reports where in the input ParseASCII gave up.
+// Line and Column are 1-based. Column counts runes and is 0 when the
+// problem is with the line as a whole.
+type ParseError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *ParseError) Error() string {
+	if e.Column == 0 {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+	}
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+// ParseASCII reads a maze in the format written by PrintMaze.
+//
+// Blank lines before and after the maze are ignored, as is indentation,
+// so mazes can be pasted from logs or written inline in Go source.
+// Trailing spaces may be trimmed. Walls are taken from the bottom and
+// right of each room, so the result always has matching walls between
+// neighbors. Exactly one S and one T must be present. Solid rooms of a
+// masked maze are drawn as #.
+func ParseASCII(r io.Reader) (*Maze, error) {
+	lines := []string{}
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		lines = append(lines, strings.TrimRight(sc.Text(), "\r"))
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	// skip leading blank lines
+	n := 0
+	for n < len(lines) && strings.TrimSpace(lines[n]) == "" {
+		n++
+	}
+	if n == len(lines) {
+		return nil, &ParseError{Line: n + 1, Msg: "no maze found"}
+	}
+
+	// The top border tells us the width
+	top, indent := trimIndent(strings.TrimRight(lines[n], " \t"))
+	for i, c := range top {
+		if c != '_' {
+			return nil, &ParseError{n + 1, indent + i + 1, fmt.Sprintf("unexpected %q in top border", c)}
+		}
+	}
+	if len(top) < 3 || len(top)%2 == 0 {
+		return nil, &ParseError{Line: n + 1, Msg: "top border must be an odd number of '_', at least 3 long"}
+	}
+	width := (len(top) - 1) / 2
+	n++
+
+	z := Maze{}
+	var start, treasure *Coordinate
+	solid := []Coordinate{}
+	for ; n < len(lines) && strings.TrimSpace(lines[n]) != ""; n++ {
+		y := len(z.rooms)
+		row, indent := trimIndent(lines[n])
+		col := func(i int) int { return indent + i + 1 }
+		// treat anything trimmed off the end as spaces
+		at := func(i int) rune {
+			if i < len(row) {
+				return row[i]
+			}
+			return ' '
+		}
+
+		if row[0] != '|' {
+			return nil, &ParseError{n + 1, col(0), fmt.Sprintf("expected left border '|', found %q", row[0])}
+		}
+		rooms := make([]Room, width)
+		i := 1
+		for x := 0; x < width; x++ {
+			r := &rooms[x]
+			switch c := at(i); c {
+			case ' ':
+			case '_':
+				r.Walls.Bottom = true
+			case '#':
+				r.Walls.Bottom = true
+				solid = append(solid, Coordinate{x, y})
+			case 'S', 'T':
+				loc := &Coordinate{x, y}
+				if c == 'S' {
+					if start != nil {
+						return nil, &ParseError{n + 1, col(i), "more than one start"}
+					}
+					start = loc
+				} else {
+					if treasure != nil {
+						return nil, &ParseError{n + 1, col(i), "more than one treasure"}
+					}
+					treasure = loc
+				}
+				if at(i+1) == combiningUnderline {
+					r.Walls.Bottom = true
+					i++
+				}
+			default:
+				return nil, &ParseError{n + 1, col(i), fmt.Sprintf("unexpected %q in room", c)}
+			}
+			i++
+
+			switch c := at(i); c {
+			case ' ':
+			case '|':
+				r.Walls.Right = true
+			default:
+				return nil, &ParseError{n + 1, col(i), fmt.Sprintf("unexpected %q where a wall belongs", c)}
+			}
+			i++
+
+			r.Walls.Left = x == 0 || rooms[x-1].Walls.Right
+			r.Walls.Top = y == 0 || z.rooms[y-1][x].Walls.Bottom
+		}
+		for ; i < len(row); i++ {
+			if row[i] != ' ' {
+				return nil, &ParseError{n + 1, col(i), fmt.Sprintf("row is wider than the top border, found %q", row[i])}
+			}
+		}
+		z.rooms = append(z.rooms, rooms)
+	}
+	if len(z.rooms) == 0 {
+		return nil, &ParseError{Line: n + 1, Msg: "maze has no rows"}
+	}
+	last := n
+
+	// only blank lines may follow
+	for ; n < len(lines); n++ {
+		if strings.TrimSpace(lines[n]) != "" {
+			return nil, &ParseError{Line: n + 1, Msg: "unexpected text after the maze"}
+		}
+	}
+
+	if start == nil {
+		return nil, &ParseError{Line: last, Msg: "maze has no start (S)"}
+	}
+	if treasure == nil {
+		return nil, &ParseError{Line: last, Msg: "maze has no treasure (T)"}
+	}
+	if len(solid) > 0 {
+		k := NewMask(width, len(z.rooms))
+		for _, c := range solid {
+			k.SetSolid(c.X, c.Y, true)
+		}
+		z.applyMask(k)
+	}
+	if err := z.SetStartPoint(start.X, start.Y); err != nil {
+		return nil, &ParseError{Line: last, Msg: "start: " + err.Error()}
+	}
+	if err := z.SetTreasure(treasure.X, treasure.Y); err != nil {
+		return nil, &ParseError{Line: last, Msg: "treasure: " + err.Error()}
+	}
+	return &z, nil
+}
+
+// Strip leading spaces and tabs, returning the rest of the line as runes
+// along with how many runes were stripped.
+func trimIndent(line string) ([]rune, int) {
+	trimmed := strings.TrimLeft(line, " \t")
+	return []rune(trimmed), len([]rune(line)) - len([]rune(trimmed))
+}
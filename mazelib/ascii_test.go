@@ -0,0 +1,128 @@
+package mazelib
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func printed(t *testing.T, m *Maze) string {
+	t.Helper()
+	var b strings.Builder
+	if err := FprintMaze(&b, m); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+func TestParseASCIIRoundTrip(t *testing.T) {
+	for _, in := range []string{
+		// start and treasure with and without a wall beneath them
+		"_________\n" +
+			"|     | |\n" +
+			"|  S  | |\n" +
+			"|_|T̲|_ _|\n",
+		"_______\n" +
+			"|S̲   T|\n" +
+			"|_ _ _|\n",
+		// solid rooms
+		"_________\n" +
+			"|#|S _  |\n" +
+			"|  _|#|_|\n" +
+			"|_ _ T̲|#|\n",
+	} {
+		m, err := ParseASCII(strings.NewReader(in))
+		if err != nil {
+			t.Errorf("ParseASCII(%q): %v", in, err)
+			continue
+		}
+		if out := printed(t, m); out != in {
+			t.Errorf("round trip changed the maze\nwant:\n%s\ngot:\n%s", in, out)
+		}
+	}
+}
+
+func TestParseASCIIIndented(t *testing.T) {
+	m, err := ParseASCII(strings.NewReader(`
+		_____
+		|S| |
+		|_ T̲|
+	`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x, y := m.Start(); x != 0 || y != 0 {
+		t.Errorf("start at (%d,%d), want (0,0)", x, y)
+	}
+	if x, y := m.End(); x != 1 || y != 1 {
+		t.Errorf("treasure at (%d,%d), want (1,1)", x, y)
+	}
+	r, _ := m.GetRoom(0, 0)
+	if want := (Survey{Top: true, Right: true, Bottom: false, Left: true}); r.Walls != want {
+		t.Errorf("start walls %+v, want %+v", r.Walls, want)
+	}
+	if errs := m.Validate(); errs != nil {
+		t.Errorf("parsed maze is invalid: %v", errs)
+	}
+}
+
+func TestParseASCIIGenerated(t *testing.T) {
+	k := NewMask(7, 5)
+	for _, c := range []Coordinate{{0, 0}, {3, 2}, {6, 4}, {4, 2}} {
+		k.SetSolid(c.X, c.Y, true)
+	}
+	rng := rand.New(rand.NewSource(1))
+	for _, m := range []*Maze{
+		FullMaze(7, 5, rng),
+		EmptyMaze(7, 5, rng),
+		FullMaskedMaze(k, rng),
+		EmptyMaskedMaze(k, rng),
+	} {
+		in := printed(t, m)
+		parsed, err := ParseASCII(strings.NewReader(in))
+		if err != nil {
+			t.Errorf("ParseASCII:\n%s\n%v", in, err)
+			continue
+		}
+		if out := printed(t, parsed); out != in {
+			t.Errorf("round trip changed the maze\nwant:\n%s\ngot:\n%s", in, out)
+		}
+		for y := 0; y < m.Height(); y++ {
+			for x := 0; x < m.Width(); x++ {
+				if m.Solid(x, y) != parsed.Solid(x, y) {
+					t.Errorf("(%d,%d): solid is %v, want %v", x, y, parsed.Solid(x, y), m.Solid(x, y))
+				}
+			}
+		}
+	}
+}
+
+func TestParseASCIIErrors(t *testing.T) {
+	for _, c := range []struct {
+		in           string
+		line, column int
+		msg          string
+	}{
+		{"", 1, 0, "no maze found"},
+		{"\n\n__x__\n", 3, 3, "unexpected 'x' in top border"},
+		{"____\n|S T|\n", 1, 0, "odd number"},
+		{"_____\n", 2, 0, "no rows"},
+		{"_____\nxS T|\n", 2, 1, "left border"},
+		{"_____\n|S T|\n|_?_|\n", 3, 3, "where a wall belongs"},
+		{"  _____\n  |S ?|\n", 2, 6, "unexpected '?' in room"},
+		{"_____\n|S|T|\n|_ _|x\n", 3, 6, "wider than the top border"},
+		{"_____\n|S S|\n", 2, 4, "more than one start"},
+		{"_____\n|S _|\n|_ _|\n", 3, 0, "no treasure"},
+		{"_____\n|S T|\n\nfoo\n", 4, 0, "after the maze"},
+	} {
+		_, err := ParseASCII(strings.NewReader(c.in))
+		perr, ok := err.(*ParseError)
+		if !ok {
+			t.Errorf("ParseASCII(%q) = %v, want a *ParseError", c.in, err)
+			continue
+		}
+		if perr.Line != c.line || perr.Column != c.column || !strings.Contains(perr.Msg, c.msg) {
+			t.Errorf("ParseASCII(%q) = %v, want line %d, column %d: ...%s...", c.in, err, c.line, c.column, c.msg)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package mazelib
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// mazeJSON is the on-disk form of a Maze.
+// Rooms are stored row by row, so rooms[y][x] is the room at (x, y).
+type mazeJSON struct {
+	Width     int        `json:"width"`
+	Height    int        `json:"height"`
+	Start     Coordinate `json:"start"`
+	Treasure  Coordinate `json:"treasure"`
+	Rooms     [][]Survey `json:"rooms"`
+	Generator string     `json:"generator,omitempty"`
+	Seed      int64      `json:"seed,omitempty"`
+	// Rows of the mask drawn as in ParseMask, for mazes that aren't rectangles.
+	Mask []string `json:"mask,omitempty"`
+}
+
+// MarshalJSON saves the layout of the maze along with its metadata.
+// Icarus's position and progress are not saved.
+func (m *Maze) MarshalJSON() ([]byte, error) {
+	out := mazeJSON{
+		Width:     m.Width(),
+		Height:    m.Height(),
+		Start:     m.start,
+		Treasure:  m.end,
+		Rooms:     make([][]Survey, m.Height()),
+		Generator: m.Generator,
+		Seed:      m.Seed,
+	}
+	if m.mask != nil {
+		out.Mask = strings.Split(m.mask.String(), "\n")
+	}
+	for y := range m.rooms {
+		out.Rooms[y] = make([]Survey, m.Width())
+		for x := range m.rooms[y] {
+			out.Rooms[y][x] = m.rooms[y][x].Walls
+		}
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON loads a maze saved with MarshalJSON.
+// Icarus wakes up at the start with no steps taken.
+func (m *Maze) UnmarshalJSON(data []byte) error {
+	var in mazeJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	if in.Width < 1 || in.Height < 1 {
+		return fmt.Errorf("invalid maze size %dx%d", in.Width, in.Height)
+	}
+	if len(in.Rooms) != in.Height {
+		return fmt.Errorf("maze has %d rows of rooms, expected %d", len(in.Rooms), in.Height)
+	}
+
+	z := Maze{rooms: make([][]Room, in.Height), Generator: in.Generator, Seed: in.Seed}
+	for y, row := range in.Rooms {
+		if len(row) != in.Width {
+			return fmt.Errorf("row %d of the maze has %d rooms, expected %d", y, len(row), in.Width)
+		}
+		z.rooms[y] = make([]Room, in.Width)
+		for x, walls := range row {
+			z.rooms[y][x].Walls = walls
+		}
+	}
+	if len(in.Mask) > 0 {
+		k, err := ParseMask(strings.NewReader(strings.Join(in.Mask, "\n")))
+		if err != nil {
+			return err
+		}
+		if err := z.applyMask(k); err != nil {
+			return err
+		}
+	}
+	if in.Start == in.Treasure {
+		return errors.New("can't have the treasure at the start")
+	}
+	if err := z.SetStartPoint(in.Start.X, in.Start.Y); err != nil {
+		return fmt.Errorf("start: %v", err)
+	}
+	if err := z.SetTreasure(in.Treasure.X, in.Treasure.Y); err != nil {
+		return fmt.Errorf("treasure: %v", err)
+	}
+	*m = z
+	return nil
+}
@@ -0,0 +1,224 @@
+package mazelib
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math/rand"
+	"strings"
+)
+
+// Mask marks which rooms of a grid are solid rock instead of part of the maze,
+// so mazes can be round, letter shaped or full of holes.
+// Solid rooms can't be entered or dug into, and GetRoom refuses to return them.
+type Mask struct {
+	solid [][]bool
+}
+
+// NewMask creates a width x height mask with every room open.
+func NewMask(width, height int) *Mask {
+	k := &Mask{solid: make([][]bool, height)}
+	for y := range k.solid {
+		k.solid[y] = make([]bool, width)
+	}
+	return k
+}
+
+func (k *Mask) Width() int  { return len(k.solid[0]) }
+func (k *Mask) Height() int { return len(k.solid) }
+
+// Solid reports whether the room at (x, y) is solid rock.
+// Rooms outside the mask count as solid.
+func (k *Mask) Solid(x, y int) bool {
+	if x < 0 || y < 0 || x >= k.Width() || y >= k.Height() {
+		return true
+	}
+	return k.solid[y][x]
+}
+
+// SetSolid makes the room at (x, y) solid or open.
+func (k *Mask) SetSolid(x, y int, solid bool) {
+	k.solid[y][x] = solid
+}
+
+// Open counts the rooms that are part of the maze.
+func (k *Mask) Open() int {
+	n := 0
+	for _, row := range k.solid {
+		for _, s := range row {
+			if !s {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// Connected reports whether every open room can be reached from every
+// other through open rooms. Generators need this to build a maze that
+// covers the whole mask.
+func (k *Mask) Connected() bool {
+	var first *Coordinate
+	for y := 0; y < k.Height() && first == nil; y++ {
+		for x := 0; x < k.Width(); x++ {
+			if !k.solid[y][x] {
+				first = &Coordinate{x, y}
+				break
+			}
+		}
+	}
+	if first == nil {
+		return false
+	}
+	seen := map[Coordinate]bool{*first: true}
+	queue := []Coordinate{*first}
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		for _, n := range []Coordinate{{c.X - 1, c.Y}, {c.X + 1, c.Y}, {c.X, c.Y - 1}, {c.X, c.Y + 1}} {
+			if !k.Solid(n.X, n.Y) && !seen[n] {
+				seen[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+	return len(seen) == k.Open()
+}
+
+// String draws the mask in the format read by ParseMask.
+func (k *Mask) String() string {
+	lines := make([]string, k.Height())
+	for y, row := range k.solid {
+		b := make([]byte, len(row))
+		for x, s := range row {
+			b[x] = '.'
+			if s {
+				b[x] = 'X'
+			}
+		}
+		lines[y] = string(b)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ParseMask reads a mask drawn as ASCII art, one character per room.
+// X or # is solid and any other character, usually . or a space, is open.
+// Lines shorter than the longest are padded with open rooms, so trailing
+// spaces may be trimmed. Blank lines at the start and end are ignored.
+func ParseMask(r io.Reader) (*Mask, error) {
+	lines := []string{}
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		lines = append(lines, strings.TrimRight(sc.Text(), "\r"))
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return nil, errors.New("mask is empty")
+	}
+
+	width := 0
+	for _, l := range lines {
+		if n := len([]rune(l)); n > width {
+			width = n
+		}
+	}
+	k := NewMask(width, len(lines))
+	for y, l := range lines {
+		for x, c := range []rune(l) {
+			k.solid[y][x] = c == 'X' || c == '#'
+		}
+	}
+	return k, nil
+}
+
+// MaskFromImage makes a mask with a room for every pixel of img.
+// Dark or transparent pixels are solid and light ones are open, so a black
+// and white PNG can be drawn in any paint program.
+func MaskFromImage(img image.Image) *Mask {
+	b := img.Bounds()
+	k := NewMask(b.Dx(), b.Dy())
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			c := color.NRGBAModel.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.NRGBA)
+			gray := color.GrayModel.Convert(color.NRGBA{c.R, c.G, c.B, 255}).(color.Gray)
+			k.solid[y][x] = c.A < 128 || gray.Y < 128
+		}
+	}
+	return k
+}
+
+// Make the rooms the mask marks as solid into solid rock: walled in on every
+// side, with their neighbors walled off from them too.
+// The maze must be the same size as the mask.
+func (z *Maze) applyMask(k *Mask) error {
+	if k.Width() != z.Width() || k.Height() != z.Height() {
+		return fmt.Errorf("mask is %dx%d but the maze is %dx%d", k.Width(), k.Height(), z.Width(), z.Height())
+	}
+	z.mask = k
+	for y := 0; y < z.Height(); y++ {
+		for x := 0; x < z.Width(); x++ {
+			if !k.solid[y][x] {
+				continue
+			}
+			z.rooms[y][x].Walls = Survey{true, true, true, true}
+			if x > 0 {
+				z.rooms[y][x-1].Walls.Right = true
+			}
+			if x < z.Width()-1 {
+				z.rooms[y][x+1].Walls.Left = true
+			}
+			if y > 0 {
+				z.rooms[y-1][x].Walls.Bottom = true
+			}
+			if y < z.Height()-1 {
+				z.rooms[y+1][x].Walls.Top = true
+			}
+		}
+	}
+	return nil
+}
+
+// Solid reports whether the room at (x, y) is solid rock rather than part of the maze.
+// Only masked mazes have solid rooms.
+func (m *Maze) Solid(x, y int) bool {
+	return m.mask != nil && m.mask.Solid(x, y)
+}
+
+// Mask returns the mask the maze was shaped with, or nil if every room is open.
+func (m *Maze) Mask() *Mask {
+	return m.mask
+}
+
+// Creates a maze without any walls, except around solid rooms,
+// in the shape of the mask. The mask needs at least two open rooms, or it panics.
+// rng picks the start and treasure locations among the open rooms.
+func EmptyMaskedMaze(k *Mask, rng *rand.Rand) *Maze {
+	z := emptyMaze(k.Width(), k.Height())
+	z.applyMask(k)
+	z.RandomizeStartAndEnd(rng)
+	return z
+}
+
+// Creates a maze with all walls in the shape of the mask.
+// The mask needs at least two open rooms, or it panics.
+// rng picks the start and treasure locations among the open rooms.
+func FullMaskedMaze(k *Mask, rng *rand.Rand) *Maze {
+	z := EmptyMaskedMaze(k, rng)
+	for y := range z.rooms {
+		for x := range z.rooms[y] {
+			z.rooms[y][x].Walls = Survey{true, true, true, true}
+		}
+	}
+	return z
+}
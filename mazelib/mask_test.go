@@ -0,0 +1,31 @@
+package mazelib
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// Too few open rooms for a start and treasure should panic, not loop forever.
+func TestMaskedMazeTooFewOpenRooms(t *testing.T) {
+	for _, open := range []int{0, 1} {
+		k := NewMask(2, 1)
+		for x := open; x < 2; x++ {
+			k.SetSolid(x, 0, true)
+		}
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%d open rooms: EmptyMaskedMaze didn't panic", open)
+				}
+			}()
+			EmptyMaskedMaze(k, rand.New(rand.NewSource(1)))
+		}()
+	}
+
+	k := NewMask(2, 1)
+	m := FullMaskedMaze(k, rand.New(rand.NewSource(1)))
+	sx, sy := m.Start()
+	if ex, ey := m.End(); sx == ex && sy == ey {
+		t.Errorf("start and treasure are both at (%d, %d)", sx, sy)
+	}
+}
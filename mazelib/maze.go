@@ -21,6 +21,7 @@ package mazelib
 import (
 	"errors"
 	"fmt"
+	"io"
 	"math/rand"
 	"os"
 	"strings"
@@ -36,8 +37,12 @@ type Coordinate struct {
 type Reply struct {
 	Survey  Survey `json:"survey"`
 	Victory bool   `json:"victory"`
+	GaveUp  bool   `json:"gaveUp"`
 	Message string `json:"message"`
 	Error   bool   `json:"error"`
+	Session string `json:"session,omitempty"`
+	// Seed the current maze was generated from.
+	Seed int64 `json:"seed"`
 }
 
 // Survey Given a location, survey surrounding locations
@@ -58,6 +63,9 @@ const (
 
 var ErrVictory error = errors.New("Victory")
 
+// Returned by the move methods once Icarus has used up his step budget.
+var ErrOutOfSteps error = errors.New("Out of steps")
+
 // Room contains the minimum informaion about a room in the maze.
 type Room struct {
 	Treasure bool
@@ -101,6 +109,7 @@ type MazeI interface {
 	SetTreasure(x, y int) error
 	LookAround() (Survey, error)
 	Discover(x, y int) (Survey, error)
+	Solid(x, y int) bool
 	Icarus() (x, y int)
 	MoveLeft() error
 	MoveRight() error
@@ -123,49 +132,87 @@ func AvgScores(in []int) int {
 
 // PrintMaze : Function to Print Maze to Console
 func PrintMaze(m MazeI) {
-	fmt.Println("_" + strings.Repeat("__", m.Width()))
+	if err := FprintMaze(os.Stdout, m); err != nil {
+		fmt.Println(err)
+		os.Exit(-1)
+	}
+}
+
+// FprintMaze writes the maze to w in the same format as PrintMaze.
+// The output can be read back with ParseASCII.
+func FprintMaze(w io.Writer, m MazeI) error {
+	if err := FprintTopBorder(w, m.Width()); err != nil {
+		return err
+	}
 	for y := 0; y < m.Height(); y++ {
-		str := ""
+		var b strings.Builder
+		b.WriteString("|")
 		for x := 0; x < m.Width(); x++ {
-			if x == 0 {
-				str += "|"
+			if m.Solid(x, y) {
+				b.WriteString("#|")
+				continue
 			}
 			r, err := m.GetRoom(x, y)
 			if err != nil {
-				fmt.Println(err)
-				os.Exit(-1)
+				return err
 			}
 			s, err := m.Discover(x, y)
 			if err != nil {
-				fmt.Println(err)
-				os.Exit(-1)
-			}
-			if s.Bottom {
-				if r.Treasure {
-					str += "T̲"
-				} else if r.Start {
-					str += "S̲"
-				} else {
-					str += "_"
-				}
-			} else {
-				if r.Treasure {
-					str += "T"
-				} else if r.Start {
-					str += "S"
-				} else {
-					str += " "
-				}
+				return err
 			}
+			writeRoom(&b, r, s)
+		}
+		b.WriteString("\n")
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-			if s.Right {
-				str += "|"
-			} else {
-				str += " "
-			}
+// FprintTopBorder writes the first line of PrintMaze output for a maze of the given width.
+// Together with FprintRow it lets a maze be printed without holding all of it in memory.
+func FprintTopBorder(w io.Writer, width int) error {
+	_, err := fmt.Fprintln(w, "_"+strings.Repeat("__", width))
+	return err
+}
+
+// FprintRow writes a single row of rooms in the same format as PrintMaze.
+func FprintRow(w io.Writer, row []Room) error {
+	var b strings.Builder
+	b.WriteString("|")
+	for x := range row {
+		writeRoom(&b, &row[x], row[x].Walls)
+	}
+	b.WriteString("\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
 
+// Draw a room's floor, with any marker, followed by its right wall.
+func writeRoom(b *strings.Builder, r *Room, s Survey) {
+	if s.Bottom {
+		if r.Treasure {
+			b.WriteString("T̲")
+		} else if r.Start {
+			b.WriteString("S̲")
+		} else {
+			b.WriteString("_")
+		}
+	} else {
+		if r.Treasure {
+			b.WriteString("T")
+		} else if r.Start {
+			b.WriteString("S")
+		} else {
+			b.WriteString(" ")
 		}
-		fmt.Println(str)
+	}
+
+	if s.Right {
+		b.WriteString("|")
+	} else {
+		b.WriteString(" ")
 	}
 }
 
@@ -175,6 +222,14 @@ type Maze struct {
 	end        Coordinate
 	icarus     Coordinate
 	StepsTaken int
+	// Maximum number of steps Icarus may take. Zero means no limit.
+	MaxSteps int
+	// Name of the generator that created the maze, if known.
+	Generator string
+	// Seed the maze was generated from, if known.
+	Seed int64
+	// Rooms that are solid rock, if any. See Mask.
+	mask *Mask
 }
 
 // Return a room from the maze
@@ -182,6 +237,9 @@ func (m *Maze) GetRoom(x, y int) (*Room, error) {
 	if x < 0 || y < 0 || x >= m.Width() || y >= m.Height() {
 		return &Room{}, errors.New("room outside of maze boundaries")
 	}
+	if m.Solid(x, y) {
+		return &Room{}, errors.New("room is solid rock")
+	}
 
 	return &m.rooms[y][x], nil
 }
@@ -251,6 +309,9 @@ func (m *Maze) LookAround() (Survey, error) {
 // Given two points, survey the room.
 // Will return error if two points are outside of the maze
 func (m *Maze) Discover(x, y int) (Survey, error) {
+	if m.Solid(x, y) {
+		return Survey{true, true, true, true}, nil
+	}
 	if r, err := m.GetRoom(x, y); err != nil {
 		return Survey{}, nil
 	} else {
@@ -258,13 +319,22 @@ func (m *Maze) Discover(x, y int) (Survey, error) {
 	}
 }
 
+// Has Icarus used up his step budget?
+func (m *Maze) outOfSteps() bool {
+	return m.MaxSteps > 0 && m.StepsTaken >= m.MaxSteps
+}
+
 // Moves Icarus's position left one step
 // Will not permit moving through walls or out of the maze
+// Will return ErrOutOfSteps once MaxSteps have been taken.
 func (m *Maze) MoveLeft() error {
 	s, e := m.LookAround()
 	if e != nil {
 		return e
 	}
+	if m.outOfSteps() {
+		return ErrOutOfSteps
+	}
 	if s.Left {
 		return errors.New("Can't walk through walls")
 	}
@@ -281,11 +351,15 @@ func (m *Maze) MoveLeft() error {
 
 // Moves Icarus's position right one step
 // Will not permit moving through walls or out of the maze
+// Will return ErrOutOfSteps once MaxSteps have been taken.
 func (m *Maze) MoveRight() error {
 	s, e := m.LookAround()
 	if e != nil {
 		return e
 	}
+	if m.outOfSteps() {
+		return ErrOutOfSteps
+	}
 	if s.Right {
 		return errors.New("Can't walk through walls")
 	}
@@ -302,11 +376,15 @@ func (m *Maze) MoveRight() error {
 
 // Moves Icarus's position up one step
 // Will not permit moving through walls or out of the maze
+// Will return ErrOutOfSteps once MaxSteps have been taken.
 func (m *Maze) MoveUp() error {
 	s, e := m.LookAround()
 	if e != nil {
 		return e
 	}
+	if m.outOfSteps() {
+		return ErrOutOfSteps
+	}
 	if s.Top {
 		return errors.New("Can't walk through walls")
 	}
@@ -323,11 +401,15 @@ func (m *Maze) MoveUp() error {
 
 // Moves Icarus's position down one step
 // Will not permit moving through walls or out of the maze
+// Will return ErrOutOfSteps once MaxSteps have been taken.
 func (m *Maze) MoveDown() error {
 	s, e := m.LookAround()
 	if e != nil {
 		return e
 	}
+	if m.outOfSteps() {
+		return ErrOutOfSteps
+	}
 	if s.Bottom {
 		return errors.New("Can't walk through walls")
 	}
@@ -342,9 +424,33 @@ func (m *Maze) MoveDown() error {
 	return nil
 }
 
+// Move Icarus one step in the direction a solver asked for:
+// "left", "right", "up" or "down".
+func (m *Maze) Move(direction string) error {
+	switch direction {
+	case "left":
+		return m.MoveLeft()
+	case "right":
+		return m.MoveRight()
+	case "up":
+		return m.MoveUp()
+	case "down":
+		return m.MoveDown()
+	}
+	return errors.New("invalid direction")
+}
+
 // Creates a maze without any walls
 // Good starting point for additive algorithms
-func EmptyMaze(xSize, ySize int) *Maze {
+// rng picks the start and treasure locations.
+func EmptyMaze(xSize, ySize int, rng *rand.Rand) *Maze {
+	z := emptyMaze(xSize, ySize)
+	z.RandomizeStartAndEnd(rng)
+	return z
+}
+
+// The rooms of an empty maze, with no start or treasure yet.
+func emptyMaze(xSize, ySize int) *Maze {
 	z := Maze{}
 	z.rooms = make([][]Room, ySize)
 	for y := 0; y < ySize; y++ {
@@ -365,15 +471,34 @@ func EmptyMaze(xSize, ySize int) *Maze {
 			}
 		}
 	}
-	z.RandomizeStartAndEnd()
 	return &z
 }
 
-func (z *Maze) RandomizeStartAndEnd() {
-	z.SetStartPoint(rand.Intn(z.Width()), rand.Intn(z.Height()))
+// Move the start and treasure to random rooms. Solid rooms are never picked.
+// Panics if the maze has fewer than two open rooms, as there is nowhere to put them.
+func (z *Maze) RandomizeStartAndEnd(rng *rand.Rand) {
+	open := 0
+	for y := 0; y < z.Height(); y++ {
+		for x := 0; x < z.Width(); x++ {
+			if !z.Solid(x, y) {
+				open++
+			}
+		}
+	}
+	if open < 2 {
+		panic(fmt.Sprintf("mazelib: a maze needs at least two open rooms for the start and treasure, this one has %d", open))
+	}
 	for {
-		tX, tY := rand.Intn(z.Width()), rand.Intn(z.Height())
-		if tX == z.start.X && tY == z.start.Y {
+		sX, sY := rng.Intn(z.Width()), rng.Intn(z.Height())
+		if z.Solid(sX, sY) {
+			continue
+		}
+		z.SetStartPoint(sX, sY)
+		break
+	}
+	for {
+		tX, tY := rng.Intn(z.Width()), rng.Intn(z.Height())
+		if (tX == z.start.X && tY == z.start.Y) || z.Solid(tX, tY) {
 			continue
 		}
 		z.SetTreasure(tX, tY)
@@ -381,10 +506,30 @@ func (z *Maze) RandomizeStartAndEnd() {
 	}
 }
 
+// Move the start and treasure to new rooms, clearing the old ones.
+// Icarus is moved to the new start.
+func (z *Maze) MoveStartAndEnd(start, end Coordinate) error {
+	if start == end {
+		return errors.New("can't have the treasure at the start")
+	}
+	if _, err := z.GetRoom(start.X, start.Y); err != nil {
+		return fmt.Errorf("start: %v", err)
+	}
+	if _, err := z.GetRoom(end.X, end.Y); err != nil {
+		return fmt.Errorf("treasure: %v", err)
+	}
+	z.rooms[z.start.Y][z.start.X].Start = false
+	z.rooms[z.end.Y][z.end.X].Treasure = false
+	z.SetStartPoint(start.X, start.Y)
+	z.SetTreasure(end.X, end.Y)
+	return nil
+}
+
 // Creates a maze with all walls
 // Good starting point for subtractive algorithms
-func FullMaze(xSize, ySize int) *Maze {
-	z := EmptyMaze(xSize, ySize)
+// rng picks the start and treasure locations.
+func FullMaze(xSize, ySize int, rng *rand.Rand) *Maze {
+	z := EmptyMaze(xSize, ySize, rng)
 
 	for y := 0; y < ySize; y++ {
 		for x := 0; x < xSize; x++ {
@@ -0,0 +1,134 @@
+package mazelib
+
+import "fmt"
+
+// The kinds of problem Validate can find.
+const (
+	AsymmetricWall = iota + 1
+	OpenBorder
+	MissingStart
+	DuplicateStart
+	MissingTreasure
+	DuplicateTreasure
+	UnreachableTreasure
+	SolidStartOrTreasure
+)
+
+// ValidationError describes a single problem with a maze.
+type ValidationError struct {
+	Kind int
+	// The room the problem was found in, where that makes sense.
+	At  Coordinate
+	Msg string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("(%d,%d): %s", e.At.X, e.At.Y, e.Msg)
+}
+
+// Validate checks that the maze is well formed:
+// walls between neighbors agree on both sides, the outer border is closed,
+// there is exactly one start and one treasure, neither in solid rock, and
+// Icarus can walk from the start to the treasure.
+// It returns every problem found, or nil if the maze is valid.
+func (m *Maze) Validate() []ValidationError {
+	var errs []ValidationError
+	add := func(kind, x, y int, format string, args ...interface{}) {
+		errs = append(errs, ValidationError{kind, Coordinate{x, y}, fmt.Sprintf(format, args...)})
+	}
+
+	starts, treasures := []Coordinate{}, []Coordinate{}
+	for y := 0; y < m.Height(); y++ {
+		for x := 0; x < m.Width(); x++ {
+			r := &m.rooms[y][x]
+			if m.Solid(x, y) && (r.Start || r.Treasure) {
+				add(SolidStartOrTreasure, x, y, "start or treasure is in solid rock")
+			}
+			if r.Start {
+				starts = append(starts, Coordinate{x, y})
+			}
+			if r.Treasure {
+				treasures = append(treasures, Coordinate{x, y})
+			}
+
+			if x == 0 && !r.Walls.Left {
+				add(OpenBorder, x, y, "left border is open")
+			}
+			if y == 0 && !r.Walls.Top {
+				add(OpenBorder, x, y, "top border is open")
+			}
+			if x == m.Width()-1 && !r.Walls.Right {
+				add(OpenBorder, x, y, "right border is open")
+			}
+			if y == m.Height()-1 && !r.Walls.Bottom {
+				add(OpenBorder, x, y, "bottom border is open")
+			}
+
+			// Only look right and down, so each shared wall is checked once
+			if x < m.Width()-1 && r.Walls.Right != m.rooms[y][x+1].Walls.Left {
+				add(AsymmetricWall, x, y, "right wall does not match left wall of (%d,%d)", x+1, y)
+			}
+			if y < m.Height()-1 && r.Walls.Bottom != m.rooms[y+1][x].Walls.Top {
+				add(AsymmetricWall, x, y, "bottom wall does not match top wall of (%d,%d)", x, y+1)
+			}
+		}
+	}
+
+	switch {
+	case len(starts) == 0:
+		add(MissingStart, 0, 0, "maze has no start")
+	case len(starts) > 1:
+		for _, c := range starts[1:] {
+			add(DuplicateStart, c.X, c.Y, "more than one start, first is at (%d,%d)", starts[0].X, starts[0].Y)
+		}
+	}
+	switch {
+	case len(treasures) == 0:
+		add(MissingTreasure, 0, 0, "maze has no treasure")
+	case len(treasures) > 1:
+		for _, c := range treasures[1:] {
+			add(DuplicateTreasure, c.X, c.Y, "more than one treasure, first is at (%d,%d)", treasures[0].X, treasures[0].Y)
+		}
+	}
+
+	if len(starts) > 0 && len(treasures) > 0 && !m.reachable(m.start, m.end) {
+		add(UnreachableTreasure, m.end.X, m.end.Y, "treasure can't be reached from the start at (%d,%d)", m.start.X, m.start.Y)
+	}
+	return errs
+}
+
+// Can Icarus walk from one room to another?
+// Follows the same rules as the Move methods: only the wall of the room
+// Icarus is standing in matters.
+func (m *Maze) reachable(from, to Coordinate) bool {
+	seen := map[Coordinate]bool{from: true}
+	queue := []Coordinate{from}
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		if c == to {
+			return true
+		}
+		w := m.rooms[c.Y][c.X].Walls
+		next := []Coordinate{}
+		if !w.Left && c.X > 0 {
+			next = append(next, Coordinate{c.X - 1, c.Y})
+		}
+		if !w.Right && c.X < m.Width()-1 {
+			next = append(next, Coordinate{c.X + 1, c.Y})
+		}
+		if !w.Top && c.Y > 0 {
+			next = append(next, Coordinate{c.X, c.Y - 1})
+		}
+		if !w.Bottom && c.Y < m.Height()-1 {
+			next = append(next, Coordinate{c.X, c.Y + 1})
+		}
+		for _, n := range next {
+			if !seen[n] {
+				seen[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package mazelib
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	for _, c := range []struct {
+		name  string
+		spoil func(m *Maze)
+		kinds []int
+		at    Coordinate
+	}{
+		{"valid", func(m *Maze) {}, nil, Coordinate{}},
+		{"asymmetric wall", func(m *Maze) {
+			m.rooms[0][0].Walls.Right = true
+		}, []int{AsymmetricWall}, Coordinate{0, 0}},
+		{"open border", func(m *Maze) {
+			m.rooms[0][1].Walls.Right = false
+		}, []int{OpenBorder}, Coordinate{1, 0}},
+		{"missing start", func(m *Maze) {
+			m.rooms[0][0].Start = false
+		}, []int{MissingStart}, Coordinate{0, 0}},
+		{"duplicate start", func(m *Maze) {
+			m.rooms[0][1].Start = true
+		}, []int{DuplicateStart}, Coordinate{1, 0}},
+		{"missing treasure", func(m *Maze) {
+			m.rooms[1][1].Treasure = false
+		}, []int{MissingTreasure}, Coordinate{0, 0}},
+		{"duplicate treasure", func(m *Maze) {
+			m.rooms[1][0].Treasure = true
+		}, []int{DuplicateTreasure}, Coordinate{1, 1}},
+		{"unreachable treasure", func(m *Maze) {
+			m.rooms[0][1].Walls.Bottom = true
+			m.rooms[1][1].Walls.Top = true
+			m.rooms[1][0].Walls.Right = true
+			m.rooms[1][1].Walls.Left = true
+		}, []int{UnreachableTreasure}, Coordinate{1, 1}},
+		{"solid treasure", func(m *Maze) {
+			k := NewMask(2, 2)
+			k.SetSolid(1, 1, true)
+			m.applyMask(k)
+		}, []int{SolidStartOrTreasure, UnreachableTreasure}, Coordinate{1, 1}},
+	} {
+		m, err := ParseASCII(strings.NewReader(`
+			_____
+			|S  |
+			|_ T̲|
+		`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.spoil(m)
+		errs := m.Validate()
+		var kinds []int
+		for _, e := range errs {
+			kinds = append(kinds, e.Kind)
+		}
+		if !reflect.DeepEqual(kinds, c.kinds) {
+			t.Errorf("%s: Validate() = %v, want kinds %v", c.name, errs, c.kinds)
+			continue
+		}
+		if len(errs) > 0 && errs[0].At != c.at {
+			t.Errorf("%s: problem found at %v, want %v", c.name, errs[0].At, c.at)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+// Package render draws mazes as SVG or PNG images, without needing a browser.
+//
+// The drawing matches the canvas renderer in the javascript page: white
+// rooms, black walls that are thicker on the border, orange start,
+// yellow treasure and pink Icarus. Solid rooms of masked mazes are gray.
+// A solve trace and a visit heatmap can be overlaid on top.
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+
+	"github.com/golangchallenge/gc6/mazelib"
+)
+
+// Options controls what is drawn on top of the maze.
+type Options struct {
+	// Size of each room in pixels. Defaults to 50.
+	CellSize int
+	// Draw the start, treasure and Icarus's current room.
+	Markers bool
+	// Rooms Icarus moved through, in order. Drawn as a line through their centers.
+	Trace []mazelib.Coordinate
+	// Number of visits to each room. Rooms are shaded by how often they were visited.
+	Heatmap map[mazelib.Coordinate]int
+}
+
+var (
+	wallColor     = color.NRGBA{0, 0, 0, 255}
+	roomColor     = color.NRGBA{255, 255, 255, 255}
+	startColor    = color.NRGBA{255, 165, 0, 255}   // orange
+	treasureColor = color.NRGBA{255, 255, 0, 255}   // yellow
+	icarusColor   = color.NRGBA{255, 192, 203, 255} // pink
+	traceColor    = color.NRGBA{0, 0, 255, 200}
+	solidColor    = color.NRGBA{64, 64, 64, 255}
+)
+
+// Visits counts how many times each room appears in a trace,
+// suitable for Options.Heatmap.
+func Visits(trace []mazelib.Coordinate) map[mazelib.Coordinate]int {
+	v := map[mazelib.Coordinate]int{}
+	for _, c := range trace {
+		v[c]++
+	}
+	return v
+}
+
+// A filled rectangle. Everything is drawn from these, so the SVG and PNG output always agree.
+type rect struct {
+	x, y, w, h int
+	c          color.NRGBA
+}
+
+// Lay out every rectangle needed to draw the maze, back to front.
+func layout(m *mazelib.Maze, opts Options) (width, height int, rects []rect) {
+	cs := opts.CellSize
+	if cs <= 0 {
+		cs = 50
+	}
+	width, height = m.Width()*cs, m.Height()*cs
+
+	fill := func(x, y int, c color.NRGBA) {
+		rects = append(rects, rect{x*cs + 2, y*cs + 2, cs - 4, cs - 4, c})
+	}
+
+	rects = append(rects, rect{0, 0, width, height, roomColor})
+	for y := 0; y < m.Height(); y++ {
+		for x := 0; x < m.Width(); x++ {
+			if m.Solid(x, y) {
+				rects = append(rects, rect{x * cs, y * cs, cs, cs, solidColor})
+			}
+		}
+	}
+
+	if len(opts.Heatmap) > 0 {
+		max := 0
+		for _, v := range opts.Heatmap {
+			if v > max {
+				max = v
+			}
+		}
+		// Row by row rather than in map order, so the same maze always draws the same image.
+		for y := 0; y < m.Height(); y++ {
+			for x := 0; x < m.Width(); x++ {
+				v := opts.Heatmap[mazelib.Coordinate{X: x, Y: y}]
+				if v <= 0 {
+					continue
+				}
+				alpha := 40 + 170*v/max
+				fill(x, y, color.NRGBA{255, 0, 0, uint8(alpha)})
+			}
+		}
+	}
+
+	if opts.Markers {
+		sx, sy := m.Start()
+		ex, ey := m.End()
+		ix, iy := m.Icarus()
+		fill(sx, sy, startColor)
+		fill(ex, ey, treasureColor)
+		if (ix != sx || iy != sy) && (ix != ex || iy != ey) {
+			fill(ix, iy, icarusColor)
+		}
+	}
+
+	// Moves are between neighboring rooms, so the trace is made of
+	// straight segments between room centers.
+	thick := cs / 10
+	if thick < 2 {
+		thick = 2
+	}
+	for i := 1; i < len(opts.Trace); i++ {
+		a, b := opts.Trace[i-1], opts.Trace[i]
+		ax, ay := a.X*cs+cs/2, a.Y*cs+cs/2
+		bx, by := b.X*cs+cs/2, b.Y*cs+cs/2
+		if ax > bx {
+			ax, bx = bx, ax
+		}
+		if ay > by {
+			ay, by = by, ay
+		}
+		rects = append(rects, rect{ax - thick/2, ay - thick/2, bx - ax + thick, by - ay + thick, traceColor})
+	}
+
+	// 2 wide borders (4 on edge)
+	for y := 0; y < m.Height(); y++ {
+		for x := 0; x < m.Width(); x++ {
+			r, _ := m.GetRoom(x, y)
+			if r.Walls.Top {
+				h := 2
+				if y == 0 {
+					h = 4
+				}
+				rects = append(rects, rect{x*cs - 2, y * cs, cs + 4, h, wallColor})
+			}
+			if r.Walls.Bottom {
+				h := 2
+				if y == m.Height()-1 {
+					h = 4
+				}
+				rects = append(rects, rect{x*cs - 2, y*cs + cs - h, cs + 4, h, wallColor})
+			}
+			if r.Walls.Left {
+				w := 2
+				if x == 0 {
+					w = 4
+				}
+				rects = append(rects, rect{x * cs, y*cs - 2, w, cs + 4, wallColor})
+			}
+			if r.Walls.Right {
+				w := 2
+				if x == m.Width()-1 {
+					w = 4
+				}
+				rects = append(rects, rect{x*cs + cs - w, y*cs - 2, w, cs + 4, wallColor})
+			}
+		}
+	}
+	return width, height, rects
+}
+
+// Image draws the maze into a new image.
+func Image(m *mazelib.Maze, opts Options) *image.NRGBA {
+	width, height, rects := layout(m, opts)
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for _, r := range rects {
+		draw.Draw(img, image.Rect(r.x, r.y, r.x+r.w, r.y+r.h), image.NewUniform(r.c), image.Point{}, draw.Over)
+	}
+	return img
+}
+
+// PNG writes the maze to w as a PNG image.
+func PNG(w io.Writer, m *mazelib.Maze, opts Options) error {
+	return png.Encode(w, Image(m, opts))
+}
+
+// SVG writes the maze to w as an SVG document.
+func SVG(w io.Writer, m *mazelib.Maze, opts Options) error {
+	width, height, rects := layout(m, opts)
+	if _, err := fmt.Fprintf(w, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n",
+		width, height, width, height); err != nil {
+		return err
+	}
+	for _, r := range rects {
+		opacity := ""
+		if r.c.A != 255 {
+			opacity = fmt.Sprintf(" fill-opacity=\"%.3f\"", float64(r.c.A)/255)
+		}
+		if _, err := fmt.Fprintf(w, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"rgb(%d,%d,%d)\"%s/>\n",
+			r.x, r.y, r.w, r.h, r.c.R, r.c.G, r.c.B, opacity); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w, "</svg>")
+	return err
+}
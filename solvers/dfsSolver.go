@@ -6,6 +6,8 @@ import (
 )
 
 type dfs struct {
+	//source of all random choices.
+	rng *rand.Rand
 	//lookup to see if a given coordinate has been visited.
 	visited map[mazelib.Coordinate]bool
 	//current path. current segment is last element.
@@ -17,9 +19,19 @@ type dfsSegment struct {
 	dir   string             // the direction I moved to get here. Empty if first cell
 }
 
-func NewDFS() MazeSolver {
+func init() {
+	Register(Registration{
+		Name:        "dfs",
+		Description: "Depth First. Never revisits a cell, backtracks out of dead ends",
+		New:         NewDFS,
+	})
+}
+
+// Create a depth first solver making its random choices with rng.
+func NewDFS(rng *rand.Rand) MazeSolver {
 	zero := mazelib.Coordinate{}
 	return &dfs{
+		rng,
 		map[mazelib.Coordinate]bool{zero: true},
 		[]*dfsSegment{{}},
 	}
@@ -49,7 +61,7 @@ func (d *dfs) Step(s mazelib.Survey) string {
 		d.current = d.current[:len(d.current)-1]
 		return reverseDir(presentCell.dir)
 	}
-	chosen := possibleDirections[rand.Intn(len(possibleDirections))]
+	chosen := possibleDirections[d.rng.Intn(len(possibleDirections))]
 	d.current = append(d.current, chosen)
 	d.visited[chosen.coord] = true
 	return chosen.dir
@@ -6,11 +6,21 @@ import (
 )
 
 type mouse struct {
+	rng     *rand.Rand
 	lastDir string
 }
 
-func NewMouse() MazeSolver {
-	return &mouse{""}
+func init() {
+	Register(Registration{
+		Name:        "mouse",
+		Description: "Random Mouse. Wanders randomly, avoiding going straight back",
+		New:         NewMouse,
+	})
+}
+
+// Create a random mouse solver making its random choices with rng.
+func NewMouse(rng *rand.Rand) MazeSolver {
+	return &mouse{rng, ""}
 }
 
 // move a random direction, giving last preference to the direction I just came from
@@ -48,6 +58,6 @@ func (m *mouse) Step(s mazelib.Survey) string {
 	if tentative != "" && len(dirs) == 0 {
 		dirs = append(dirs, tentative)
 	}
-	m.lastDir = dirs[rand.Intn(len(dirs))]
+	m.lastDir = dirs[m.rng.Intn(len(dirs))]
 	return m.lastDir
 }
@@ -0,0 +1,51 @@
+package solvers
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// Registration describes a solver that can be created by name.
+type Registration struct {
+	Name        string
+	Description string
+	New         func(rng *rand.Rand) MazeSolver
+}
+
+var registry = map[string]Registration{}
+var registryOrder = []string{}
+
+// Register makes a solver available by name.
+// Registering the same name twice panics.
+func Register(r Registration) {
+	if _, dup := registry[r.Name]; dup {
+		panic("solvers: Register called twice for " + r.Name)
+	}
+	registry[r.Name] = r
+	registryOrder = append(registryOrder, r.Name)
+}
+
+// Lookup finds a registered solver by name.
+func Lookup(name string) (Registration, bool) {
+	r, ok := registry[name]
+	return r, ok
+}
+
+// List returns every registered solver, in the order they were registered.
+func List() []Registration {
+	all := make([]Registration, 0, len(registryOrder))
+	for _, name := range registryOrder {
+		all = append(all, registry[name])
+	}
+	return all
+}
+
+// New creates a fresh instance of the named solver, making its random choices with rng.
+func New(name string, rng *rand.Rand) (MazeSolver, error) {
+	r, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown solver %q. Choose one of: %s", name, strings.Join(registryOrder, ", "))
+	}
+	return r.New(rng), nil
+}
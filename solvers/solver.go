@@ -7,3 +7,25 @@ import (
 type MazeSolver interface {
 	Step(mazelib.Survey) string
 }
+
+// Solve lets a solver loose in a maze, in memory, until it finds the treasure.
+// Returns the steps taken, or mazelib.ErrOutOfSteps if the solver gave up.
+// If onMove is not nil it is called with Icarus's position after every step.
+func Solve(m *mazelib.Maze, solver MazeSolver, onMove func(x, y int)) (int, error) {
+	endX, endY := m.End()
+	s, err := m.Discover(m.Icarus())
+	for err == nil {
+		if err = m.Move(solver.Step(s)); err != nil {
+			break
+		}
+		x, y := m.Icarus()
+		if onMove != nil {
+			onMove(x, y)
+		}
+		if x == endX && y == endY {
+			return m.StepsTaken, nil
+		}
+		s, err = m.Discover(x, y)
+	}
+	return m.StepsTaken, err
+}